@@ -0,0 +1,64 @@
+package bidi
+
+// brackets maps each opening paired bracket to its closing bracket, as listed
+// in the Unicode BidiBrackets.txt data file.
+var brackets = map[rune]rune{
+	'(': ')', '[': ']', '{': '}',
+	'༺': '༻', '༼': '༽', '᚛': '᚜',
+	'⁅': '⁆', '⁽': '⁾', '₍': '₎',
+	'⌈': '⌉', '⌊': '⌋', '\u2329': '\u232A',
+	'❨': '❩', '❪': '❫', '❬': '❭',
+	'❮': '❯', '❰': '❱', '❲': '❳',
+	'❴': '❵', '⟅': '⟆', '⟦': '⟧',
+	'⟨': '⟩', '⟪': '⟫', '⟬': '⟭',
+	'⟮': '⟯', '⦃': '⦄', '⦅': '⦆',
+	'⦇': '⦈', '⦉': '⦊', '⦋': '⦌',
+	'⦍': '⦐', '⦏': '⦎', '⦑': '⦒',
+	'⦓': '⦔', '⦕': '⦖', '⦗': '⦘',
+	'⧘': '⧙', '⧚': '⧛', '⧼': '⧽',
+	'⸢': '⸣', '⸤': '⸥', '⸦': '⸧',
+	'⸨': '⸩', '⹕': '⹖', '⹗': '⹘',
+	'⹙': '⹚', '⹛': '⹜', '\u3008': '\u3009',
+	'《': '》', '「': '」', '『': '』',
+	'【': '】', '〔': '〕', '〖': '〗',
+	'〘': '〙', '〚': '〛', '﹙': '﹚',
+	'﹛': '﹜', '﹝': '﹞', '（': '）',
+	'［': '］', '｛': '｝', '｟': '｠',
+	'｢': '｣',
+}
+
+// closers holds every closing paired bracket.
+var closers = make(map[rune]bool, len(brackets))
+
+// mirrors maps characters to the glyph shown in their place when they are
+// laid out right to left, as rule L4 requires. It covers the paired brackets
+// and the most common of the other mirrored characters.
+var mirrors = map[rune]rune{
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+	'≤': '≥', '≥': '≤',
+	'≪': '≫', '≫': '≪',
+	'∈': '∋', '∋': '∈',
+	'⊂': '⊃', '⊃': '⊂',
+}
+
+func init() {
+	for opening, closing := range brackets {
+		closers[closing] = true
+		mirrors[opening] = closing
+		mirrors[closing] = opening
+	}
+}
+
+// canonicalBracket maps the angle brackets at U+2329 and U+232A to their
+// canonical equivalents, which rule BD16 treats as the same brackets.
+func canonicalBracket(r rune) rune {
+	switch r {
+	case '\u2329':
+		return '\u3008'
+	case '\u232A':
+		return '\u3009'
+	}
+	return r
+}
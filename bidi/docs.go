@@ -1,12 +1,29 @@
-// Package bidi provides bidirectional text support for Hebrew and Arabic text.
+// Package bidi provides bidirectional text support for Hebrew, Arabic and the
+// other right-to-left scripts.
 // It handles automatic text direction detection and proper rendering of mixed
 // left-to-right (LTR) and right-to-left (RTL) content.
 //
 // The package includes:
 //   - String: A custom string type that automatically wraps RTL text with
 //     Unicode directional markers for proper display
-//   - Writer: An io.Writer that processes bidirectional text and reverses
-//     RTL sequences for correct rendering
+//   - Strategy: The ways RTL text can be marked instead, with isolates, HTML
+//     or not at all, and MarshalJSON to marshal values with one of them
+//   - Writer: An io.Writer that lays out bidirectional text in visual order
+//     for correct rendering
+//   - Paragraph and Visual: An implementation of the Unicode Bidirectional
+//     Algorithm (UAX #9), which resolves the direction of a paragraph and
+//     lays it out, or each line of it, in visual order
+//   - Layout: Wraps paragraphs to the width of a terminal before laying
+//     out each line in visual order, aligning RTL lines on the right
+//
+// Visual order keeps numbers, mirrored brackets and punctuation in their
+// place inside RTL text, which a plain reversal of RTL runs does not, so
+// "פרק 12" is shown as "12 קרפ" rather than "21 קרפ".
+//
+// Text is classified by the bidi classes of its characters rather than their
+// scripts, so Syriac, Thaana, N'Ko and the Arabic presentation forms are
+// handled along with Hebrew and Arabic. Direction gives the direction of a
+// piece of text, or Auto when it has no letters to tell it by.
 //
 // This is particularly useful for applications dealing with Hebrew or Arabic
 // text that needs to be displayed correctly in mixed-language contexts.
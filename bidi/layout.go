@@ -0,0 +1,156 @@
+package bidi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Layout lays out bidirectional text for a terminal that shows characters in
+// the order they are given, wrapping it to the width of the terminal.
+//
+// Wrapping has to happen before reordering: reversing a whole paragraph and
+// then cutting it into lines puts the lines of an RTL paragraph in the wrong
+// order. Layout breaks each paragraph into lines in logical order, then lays
+// out each line in visual order on its own.
+type Layout struct {
+	// Width is the number of columns lines are wrapped at. Lines are not
+	// wrapped when it is 0.
+	Width int
+
+	// Dir is the direction of each paragraph. When it is Auto, the
+	// direction of each paragraph is taken from its own text.
+	Dir Dir
+
+	// Logical leaves each line in logical order, for terminals that lay out
+	// bidirectional text themselves. Lines are still wrapped, but they are
+	// not aligned.
+	Logical bool
+}
+
+// Lines lays out s, returning one element per line. Each paragraph of s is
+// wrapped at spaces, or anywhere in a word that does not fit on a line of its
+// own. When Width is set, the lines of RTL paragraphs are padded on the left
+// so that they line up on the right.
+func (l Layout) Lines(s string) []string {
+	var lines []string
+	for {
+		end, next := paragraphEnd(s)
+		lines = append(lines, l.paragraph(s[:end])...)
+		if next == len(s) {
+			return lines
+		}
+		s = s[next:]
+	}
+}
+
+// String lays out s as Lines does, joining the lines with newlines.
+func (l Layout) String(s string) string {
+	return strings.Join(l.Lines(s), "\n")
+}
+
+func (l Layout) paragraph(s string) []string {
+	if l.Logical {
+		var lines []string
+		for _, line := range l.breaks(s) {
+			lines = append(lines, s[line[0]:line[1]])
+		}
+		return lines
+	}
+
+	p := NewParagraph(s, l.Dir)
+	var lines []string
+	for _, line := range l.breaks(s) {
+		text := stripMarks(p.Line(line[0], line[1]))
+		if pad := l.Width - StringWidth(text); l.Width > 0 && pad > 0 && p.Dir() == RightToLeft {
+			text = strings.Repeat(" ", pad) + text
+		}
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+// breaks returns the byte offsets of the start and end of each line of the
+// paragraph s when it is wrapped. Spaces where a line is broken are left out
+// of both lines.
+func (l Layout) breaks(s string) [][2]int {
+	if l.Width <= 0 {
+		return [][2]int{{0, len(s)}}
+	}
+
+	var (
+		lines      [][2]int
+		start, end int // the start of the current line and the end of its last word
+		width      int // the width of the current line, with any spaces after its last word
+	)
+	for _, word := range words(s) {
+		w := StringWidth(s[word[0]:word[1]])
+		if word.space() {
+			width += w
+			continue
+		}
+
+		// The spaces before a word that starts a new line are dropped.
+		if end > start && width+w > l.Width {
+			lines = append(lines, [2]int{start, end})
+			start, width = word[0], 0
+		}
+
+		// A word too wide for a line of its own is broken wherever it
+		// has to be.
+		for i, r := range s[word[0]:word[1]] {
+			rw := runeWidth(r)
+			if i := word[0] + i; width+rw > l.Width && i > start {
+				lines = append(lines, [2]int{start, i})
+				start, width = i, 0
+			}
+			width += rw
+		}
+		end = word[1]
+	}
+	return append(lines, [2]int{start, end})
+}
+
+// span is a run of text between two byte offsets.
+type span [3]int
+
+func (w span) space() bool { return w[2] == 1 }
+
+// words splits s into runs of spaces and runs of other characters.
+func words(s string) []span {
+	var spans []span
+	for i, r := range s {
+		kind := 0
+		if unicode.IsSpace(r) {
+			kind = 1
+		}
+		if n := len(spans); n > 0 && spans[n-1][2] == kind {
+			spans[n-1][1] = i + len(string(r))
+			continue
+		}
+		spans = append(spans, span{i, i + len(string(r)), kind})
+	}
+	return spans
+}
+
+// runeWidth returns the number of columns r takes up on a terminal. Combining
+// marks, such as niqqud, take up none, as they are drawn over the letter
+// before them.
+func runeWidth(r rune) int {
+	if isMark(r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+	return runewidth.RuneWidth(r)
+}
+
+// StringWidth returns the number of columns s takes up on a terminal.
+// Combining marks and formatting characters, such as directional marks, take
+// up none.
+func StringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
@@ -0,0 +1,123 @@
+package bidi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout_Lines(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   Layout
+		input    string
+		expected []string
+	}{
+		{
+			name:     "no width",
+			layout:   Layout{},
+			input:    "בראשית ברא אלהים",
+			expected: []string{"םיהלא ארב תישארב"},
+		},
+		{
+			name:     "empty",
+			layout:   Layout{Width: 10},
+			input:    "",
+			expected: []string{""},
+		},
+		{
+			name:     "LTR wrapped at spaces",
+			layout:   Layout{Width: 11},
+			input:    "In the beginning God created",
+			expected: []string{"In the", "beginning", "God created"},
+		},
+		{
+			name:   "RTL wrapped before reordering and aligned right",
+			layout: Layout{Width: 11},
+			input:  "בראשית ברא אלהים את השמים",
+			expected: []string{
+				" ארב תישארב",
+				"   תא םיהלא",
+				"      םימשה",
+			},
+		},
+		{
+			name:     "number stays with its line",
+			layout:   Layout{Width: 8},
+			input:    "פרק 12 פסוק 3",
+			expected: []string{"  12 קרפ", "  3 קוספ"},
+		},
+		{
+			name:     "long word broken",
+			layout:   Layout{Width: 4},
+			input:    "abcdefghij",
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "niqqud kept with its letter",
+			layout:   Layout{Width: 2},
+			input:    "בְּרֵא",
+			expected: []string{"רֵבְּ", " א"},
+		},
+		{
+			name:     "leading spaces kept",
+			layout:   Layout{Width: 10},
+			input:    "  indented text",
+			expected: []string{"  indented", "text"},
+		},
+		{
+			name:     "each paragraph wrapped on its own",
+			layout:   Layout{Width: 7},
+			input:    "Genesis\nבראשית",
+			expected: []string{"Genesis", " תישארב"},
+		},
+		{
+			name:     "forced direction",
+			layout:   Layout{Width: 12, Dir: LeftToRight},
+			input:    "בראשית 1:1",
+			expected: []string{"1:1 תישארב"},
+		},
+		{
+			name:     "logical order",
+			layout:   Layout{Width: 6, Logical: true},
+			input:    "בראשית ברא",
+			expected: []string{"בראשית", "ברא"},
+		},
+		{
+			name:     "directional marks dropped",
+			layout:   Layout{},
+			input:    "Read \u200Fבראשית\u200E today",
+			expected: []string{"Read תישארב today"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.layout.Lines(tt.input))
+		})
+	}
+}
+
+func TestLayout_LinesFitWidth(t *testing.T) {
+	input := "וַיֹּאמֶר אֱלֹהִים יְהִי אוֹר וַיְהִי אוֹר, and God said: let there be light (Genesis 1:3)"
+	for width := 1; width <= 40; width++ {
+		lines := Layout{Width: width}.Lines(input)
+		for _, line := range lines {
+			if w := StringWidth(line); w > width {
+				t.Errorf("width %d: line %q is %d columns wide", width, line, w)
+			}
+		}
+	}
+}
+
+func ExampleLayout() {
+	layout := Layout{Width: 12}
+	for _, line := range layout.Lines("בראשית ברא אלהים את השמים") {
+		fmt.Printf("|%s|\n", line)
+	}
+	// Output:
+	// |  ארב תישארב|
+	// |    תא םיהלא|
+	// |       םימשה|
+}
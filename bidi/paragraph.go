@@ -0,0 +1,326 @@
+package bidi
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	xbidi "golang.org/x/text/unicode/bidi"
+)
+
+// Dir is the direction of a paragraph of text.
+type Dir int
+
+const (
+	// Auto takes the direction of a paragraph from its first strong
+	// character, as rules P2 and P3 of the Unicode Bidirectional Algorithm
+	// do.
+	Auto Dir = iota
+	LeftToRight
+	RightToLeft
+)
+
+func (d Dir) String() string {
+	switch d {
+	case LeftToRight:
+		return "ltr"
+	case RightToLeft:
+		return "rtl"
+	}
+	return "auto"
+}
+
+// maxDepth is the deepest embedding level allowed by rule BD2.
+const maxDepth = 125
+
+// maxBrackets is the number of open brackets rule BD16 keeps track of.
+const maxBrackets = 63
+
+// Paragraph is a paragraph of text whose embedding levels have been resolved
+// by the Unicode Bidirectional Algorithm (UAX #9). It lays out the paragraph,
+// or any line of it, in visual order, for terminals and other displays that
+// show characters in the order they are given.
+type Paragraph struct {
+	text    string
+	runes   []rune
+	offsets []int // the byte offset of each rune, followed by len(text)
+	types   []xbidi.Class
+	levels  []int8
+	level   int8
+}
+
+// NewParagraph resolves the embedding levels of s, which should hold a single
+// paragraph. Use Visual for text that may hold several.
+func NewParagraph(s string, dir Dir) *Paragraph {
+	p := &Paragraph{text: s}
+	for i, r := range s {
+		p.runes = append(p.runes, r)
+		p.offsets = append(p.offsets, i)
+		p.types = append(p.types, class(r))
+	}
+	p.offsets = append(p.offsets, len(s))
+
+	r := resolver{
+		runes:   p.runes,
+		initial: p.types,
+		types:   slices.Clone(p.types),
+		levels:  make([]int8, len(p.types)),
+	}
+	r.matchIsolates()
+	switch dir {
+	case LeftToRight:
+		r.level = 0
+	case RightToLeft:
+		r.level = 1
+	default:
+		r.level, _ = r.firstStrong(0, len(p.types))
+	}
+	r.resolve()
+
+	p.levels = r.levels
+	p.level = r.level
+	return p
+}
+
+// Dir returns the direction of the paragraph.
+func (p *Paragraph) Dir() Dir {
+	if p.level&1 == 1 {
+		return RightToLeft
+	}
+	return LeftToRight
+}
+
+// String returns the whole paragraph laid out as a single line.
+func (p *Paragraph) String() string {
+	return p.Line(0, len(p.text))
+}
+
+// Line returns the text between the byte offsets start and end laid out as a
+// single line, in visual order. Lines are reordered on their own, as rules L1
+// and L2 require, so a paragraph that has been wrapped is laid out one line
+// at a time. Offsets that fall inside a character are moved to its start.
+func (p *Paragraph) Line(start, end int) string {
+	from, to := p.runeIndex(start), p.runeIndex(end)
+	if from >= to {
+		return ""
+	}
+
+	levels := p.lineLevels(from, to)
+	var (
+		b     strings.Builder
+		marks []rune
+	)
+	b.Grow(p.offsets[to] - p.offsets[from])
+	for _, i := range visualOrder(levels) {
+		r := p.runes[from+i]
+		if levels[i]&1 == 1 {
+			if m, ok := mirrors[r]; ok {
+				r = m
+			}
+
+			// Reversing a run puts combining marks, such as niqqud,
+			// before the letters they belong to. They are moved back
+			// after them, where terminals expect them, as rule L3
+			// allows.
+			if isMark(r) {
+				marks = append(marks, r)
+				continue
+			}
+		}
+		b.WriteRune(r)
+		for len(marks) > 0 {
+			b.WriteRune(marks[len(marks)-1])
+			marks = marks[:len(marks)-1]
+		}
+	}
+	for _, r := range marks {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me)
+}
+
+// runeIndex returns the index of the rune at the byte offset off.
+func (p *Paragraph) runeIndex(off int) int {
+	off = max(0, min(off, len(p.text)))
+	i, found := slices.BinarySearch(p.offsets, off)
+	if !found {
+		i--
+	}
+	return i
+}
+
+// lineLevels returns the levels of the runes of a line, between the rune
+// indexes start and end, with rule L1 applied.
+func (p *Paragraph) lineLevels(start, end int) []int8 {
+	levels := slices.Clone(p.levels[start:end])
+	trailing := true
+	for i := end - 1; i >= start; i-- {
+		switch t := p.types[i]; {
+		case t == xbidi.B || t == xbidi.S:
+			levels[i-start] = p.level
+			trailing = true
+		case trailing && isWhitespace(t):
+			levels[i-start] = p.level
+		default:
+			trailing = false
+		}
+	}
+	return levels
+}
+
+// visualOrder returns the indexes of levels in the order they are shown, by
+// reversing each run of characters at or above a level, from the highest
+// level down to the lowest odd one, as rule L2 requires.
+func visualOrder(levels []int8) []int {
+	order := make([]int, len(levels))
+	var highest, lowestOdd int8 = 0, maxDepth + 2
+	for i, l := range levels {
+		order[i] = i
+		highest = max(highest, l)
+		if l&1 == 1 {
+			lowestOdd = min(lowestOdd, l)
+		}
+	}
+
+	levels = slices.Clone(levels)
+	for l := highest; l >= lowestOdd; l-- {
+		for i := 0; i < len(levels); i++ {
+			if levels[i] < l {
+				continue
+			}
+			j := i
+			for j < len(levels) && levels[j] >= l {
+				j++
+			}
+			slices.Reverse(order[i:j])
+			slices.Reverse(levels[i:j])
+			i = j
+		}
+	}
+	return order
+}
+
+// Visual returns s laid out in visual order. Each paragraph of s, as ended by
+// a newline or other paragraph separator, is laid out as a single line. When
+// dir is Auto, the direction of each paragraph is taken from its own text.
+func Visual(s string, dir Dir) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		end, next := paragraphEnd(s)
+		b.WriteString(NewParagraph(s[:end], dir).String())
+		b.WriteString(s[end:next])
+		s = s[next:]
+	}
+	return b.String()
+}
+
+// ParagraphDir returns the direction of the first paragraph of s, taken from
+// its first strong character outside of any isolate. It returns LeftToRight
+// when there is none, as rule P3 does.
+func ParagraphDir(s string) Dir {
+	depth := 0
+	for _, r := range s {
+		switch t := class(r); {
+		case t == xbidi.B:
+			return LeftToRight
+		case isIsolateInitiator(t):
+			depth++
+		case t == xbidi.PDI:
+			depth = max(0, depth-1)
+		case depth > 0:
+		case t == xbidi.L:
+			return LeftToRight
+		case t == xbidi.R || t == xbidi.AL:
+			return RightToLeft
+		}
+	}
+	return LeftToRight
+}
+
+// Direction returns the direction of s, taken from its first strong
+// character outside of any isolate, in whichever paragraph it is. Unlike
+// ParagraphDir, it returns Auto when s has no strong characters at all, such
+// as a bare number, leaving the direction to the caller.
+func Direction(s string) Dir {
+	depth := 0
+	for _, r := range s {
+		switch t := class(r); {
+		case t == xbidi.B:
+			depth = 0
+		case isIsolateInitiator(t):
+			depth++
+		case t == xbidi.PDI:
+			depth = max(0, depth-1)
+		case depth > 0:
+		case t == xbidi.L:
+			return LeftToRight
+		case t == xbidi.R || t == xbidi.AL:
+			return RightToLeft
+		}
+	}
+	return Auto
+}
+
+// ParseDir returns the direction named by s, "ltr" or "rtl", as Sefaria
+// gives the direction of a version. Anything else is Auto.
+func ParseDir(s string) Dir {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "ltr":
+		return LeftToRight
+	case "rtl":
+		return RightToLeft
+	}
+	return Auto
+}
+
+// paragraphEnd returns the byte offset where the first paragraph of s ends,
+// and where the next one begins after its separator.
+func paragraphEnd(s string) (end, next int) {
+	for i, r := range s {
+		if class(r) != xbidi.B {
+			continue
+		}
+		if strings.HasPrefix(s[i:], "\r\n") {
+			return i, i + 2
+		}
+		return i, i + len(string(r))
+	}
+	return len(s), len(s)
+}
+
+func class(r rune) xbidi.Class {
+	p, _ := xbidi.LookupRune(r)
+	return p.Class()
+}
+
+func isIsolateInitiator(t xbidi.Class) bool {
+	return t == xbidi.LRI || t == xbidi.RLI || t == xbidi.FSI
+}
+
+// isRemovedByX9 reports whether characters of type t are removed by rule X9.
+func isRemovedByX9(t xbidi.Class) bool {
+	switch t {
+	case xbidi.LRE, xbidi.RLE, xbidi.LRO, xbidi.RLO, xbidi.PDF, xbidi.BN:
+		return true
+	}
+	return false
+}
+
+// isWhitespace reports whether characters of type t are reset to the
+// paragraph level by rule L1 when they end a line or come before a separator.
+func isWhitespace(t xbidi.Class) bool {
+	return t == xbidi.WS || t == xbidi.PDI || isIsolateInitiator(t) || isRemovedByX9(t)
+}
+
+// typeForLevel returns the embedding direction of a level.
+func typeForLevel(l int8) xbidi.Class {
+	if l&1 == 1 {
+		return xbidi.R
+	}
+	return xbidi.L
+}
@@ -0,0 +1,263 @@
+package bidi
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xbidi "golang.org/x/text/unicode/bidi"
+)
+
+//go:generate curl -fsSL -o testdata/BidiCharacterTest.txt https://www.unicode.org/Public/15.0.0/ucd/BidiCharacterTest.txt
+
+// TestBidiCharacterTest runs the conformance test of the Unicode Character
+// Database against the version of Unicode the bidi class tables are from.
+// When golang.org/x/text moves to a newer version, update the URL above and
+// run go generate to fetch its test.
+func TestBidiCharacterTest(t *testing.T) {
+	f, err := os.Open("testdata/BidiCharacterTest.txt")
+	require.NoError(t, err, "testdata/BidiCharacterTest.txt is missing; run go generate to fetch it")
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	require.True(t, scanner.Scan(), "empty BidiCharacterTest.txt")
+	want := "# BidiCharacterTest-" + xbidi.UnicodeVersion + ".txt"
+	require.Equal(t, want, strings.TrimSpace(scanner.Text()), "BidiCharacterTest.txt is for another version of Unicode")
+
+	runBidiCharacterTest(t, scanner, 1)
+}
+
+// TestBidiCases runs this package's own cases, which are in the format of
+// BidiCharacterTest.txt.
+func TestBidiCases(t *testing.T) {
+	f, err := os.Open("testdata/cases.txt")
+	require.NoError(t, err)
+	defer f.Close()
+
+	runBidiCharacterTest(t, bufio.NewScanner(f), 0)
+}
+
+// runBidiCharacterTest checks each line of a file in the format of
+// BidiCharacterTest.txt read from scanner, which has already read line
+// lines.
+func runBidiCharacterTest(t *testing.T, scanner *bufio.Scanner, line int) {
+	failures := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ";")
+		require.Len(t, fields, 5, "line %d", line)
+
+		var s strings.Builder
+		for _, cp := range strings.Fields(fields[0]) {
+			r, err := strconv.ParseInt(cp, 16, 32)
+			require.NoError(t, err, "line %d", line)
+			s.WriteRune(rune(r))
+		}
+		dir := map[string]Dir{"0": LeftToRight, "1": RightToLeft, "2": Auto}[fields[1]]
+
+		p := NewParagraph(s.String(), dir)
+		levels := p.lineLevels(0, len(p.runes))
+
+		var resolved, order []string
+		for i, l := range levels {
+			if isRemovedByX9(p.types[i]) {
+				resolved = append(resolved, "x")
+			} else {
+				resolved = append(resolved, strconv.Itoa(int(l)))
+			}
+		}
+		for _, i := range visualOrder(levels) {
+			if !isRemovedByX9(p.types[i]) {
+				order = append(order, strconv.Itoa(i))
+			}
+		}
+
+		ok := assert.Equal(t, fields[2], strconv.Itoa(int(p.level)), "line %d: paragraph level of %s", line, fields[0])
+		ok = assert.Equal(t, fields[3], strings.Join(resolved, " "), "line %d: levels of %s", line, fields[0]) && ok
+		ok = assert.Equal(t, fields[4], strings.Join(order, " "), "line %d: order of %s", line, fields[0]) && ok
+		if !ok {
+			// The conformance test has tens of thousands of lines, so stop
+			// before the failures bury each other.
+			if failures++; failures == 20 {
+				t.Fatalf("too many failures, stopped at line %d", line)
+			}
+		}
+	}
+	require.NoError(t, scanner.Err())
+}
+
+func TestVisual(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		dir      Dir
+		expected string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "LTR only text",
+			input:    "Hello World",
+			expected: "Hello World",
+		},
+		{
+			name:     "Hebrew words",
+			input:    "שלום עולם",
+			expected: "םלוע םולש",
+		},
+		{
+			name:     "number inside Hebrew",
+			input:    "פרק 12",
+			expected: "12 קרפ",
+		},
+		{
+			name:     "number range inside Hebrew",
+			input:    "פרקים 1-3",
+			expected: "1-3 םיקרפ",
+		},
+		{
+			name:     "mirrored brackets",
+			input:    "בראשית (35)",
+			expected: "(35) תישארב",
+		},
+		{
+			name:     "niqqud kept after its letter",
+			input:    "בְּרֵאשִׁית",
+			expected: "תישִׁארֵבְּ",
+		},
+		{
+			name:     "punctuation at the end of a run",
+			input:    "שלום, עולם.",
+			expected: ".םלוע ,םולש",
+		},
+		{
+			name:     "Hebrew inside English",
+			input:    "The word שלום עולם means hello world.",
+			expected: "The word םלוע םולש means hello world.",
+		},
+		{
+			name:     "Hebrew followed by a number inside English",
+			input:    "See בראשית 1:1 today",
+			expected: "See 1:1 תישארב today",
+		},
+		{
+			name:     "forced direction",
+			input:    "Hello שלום",
+			dir:      RightToLeft,
+			expected: "םולש Hello",
+		},
+		{
+			name:     "each paragraph takes its own direction",
+			input:    "Genesis בראשית\nבראשית Genesis\r\nשלום",
+			expected: "Genesis תישארב\nGenesis תישארב\r\nםולש",
+		},
+		{
+			name:     "trailing newline",
+			input:    "שלום\n",
+			expected: "םולש\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Visual(tt.input, tt.dir))
+		})
+	}
+}
+
+func TestParagraphDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Dir
+	}{
+		{"empty", "", LeftToRight},
+		{"English", "Genesis 1:1", LeftToRight},
+		{"Hebrew", "בראשית א׳:א׳", RightToLeft},
+		{"Arabic", "مرحبا", RightToLeft},
+		{"number before Hebrew", "12 פרקים", RightToLeft},
+		{"number only", "1:1", LeftToRight},
+		{"isolate skipped", "⁦Genesis⁩ בראשית", RightToLeft},
+		{"unmatched isolate", "⁧Genesis בראשית", LeftToRight},
+		{"first paragraph only", "1\nבראשית", LeftToRight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParagraphDir(tt.input))
+			assert.Equal(t, tt.expected, NewParagraph(tt.input, Auto).Dir())
+		})
+	}
+}
+
+func TestDirection(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Dir
+	}{
+		{"empty", "", Auto},
+		{"number only", "1:1", Auto},
+		{"English", "Genesis 1:1", LeftToRight},
+		{"Hebrew with niqqud", "בְּרֵאשִׁית", RightToLeft},
+		{"Yiddish", "אין אָנהייב", RightToLeft},
+		{"Arabic presentation forms", "ﻣﺮﺣﺒﺎ", RightToLeft},
+		{"Persian", "سلام", RightToLeft},
+		{"Syriac", "ܫܠܡܐ", RightToLeft},
+		{"Thaana", "ދިވެހި", RightToLeft},
+		{"N'Ko", "ߒߞߏ", RightToLeft},
+		{"number before Hebrew", "12 פרקים", RightToLeft},
+		{"isolate skipped", "⁦Genesis⁩ בראשית", RightToLeft},
+		{"later paragraph", "1\nבראשית", RightToLeft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Direction(tt.input))
+		})
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	assert.Equal(t, RightToLeft, ParseDir("rtl"))
+	assert.Equal(t, LeftToRight, ParseDir("LTR"))
+	assert.Equal(t, Auto, ParseDir(""))
+	assert.Equal(t, Auto, ParseDir("sideways"))
+	assert.Equal(t, RightToLeft, ParseDir(RightToLeft.String()))
+}
+
+func TestParagraph_Line(t *testing.T) {
+	s := "בראשית ברא אלהים את השמים"
+	p := NewParagraph(s, Auto)
+
+	// Each line is laid out on its own, with the trailing space of the
+	// first line kept at its end.
+	mid := strings.Index(s, "אלהים")
+	assert.Equal(t, " ארב תישארב", p.Line(0, mid))
+	assert.Equal(t, "םימשה תא םיהלא", p.Line(mid, len(s)))
+	assert.Equal(t, p.String(), p.Line(0, len(s)))
+
+	// Offsets inside a character are moved to its start.
+	assert.Equal(t, "ב", p.Line(1, 3))
+	assert.Equal(t, "", p.Line(4, 2))
+}
+
+func ExampleVisual() {
+	fmt.Println(Visual("פרק 12 (בראשית)", Auto))
+	fmt.Println(Visual("See בראשית 1:1", Auto))
+	// Output:
+	// (תישארב) 12 קרפ
+	// See 1:1 תישארב
+}
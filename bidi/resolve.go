@@ -0,0 +1,548 @@
+package bidi
+
+import (
+	"slices"
+
+	xbidi "golang.org/x/text/unicode/bidi"
+)
+
+// resolver resolves the embedding levels of a paragraph, following rules P2
+// through I2 of UAX #9.
+type resolver struct {
+	runes   []rune
+	initial []xbidi.Class // the original type of each character
+	types   []xbidi.Class // the type of each character as it is resolved
+	levels  []int8
+	level   int8 // the paragraph embedding level
+
+	// The index of the PDI matching each isolate initiator, or len(runes)
+	// when there is none, and the index of the isolate initiator matching
+	// each PDI, or -1 when there is none.
+	matchingPDI       []int
+	matchingInitiator []int
+}
+
+// matchIsolates pairs isolate initiators with their PDIs, as rule BD9
+// describes.
+func (r *resolver) matchIsolates() {
+	n := len(r.initial)
+	r.matchingPDI = make([]int, n)
+	r.matchingInitiator = make([]int, n)
+
+	var open []int
+	for i, t := range r.initial {
+		r.matchingPDI[i] = -1
+		r.matchingInitiator[i] = -1
+		switch {
+		case isIsolateInitiator(t):
+			r.matchingPDI[i] = n
+			open = append(open, i)
+		case t == xbidi.PDI && len(open) > 0:
+			j := open[len(open)-1]
+			open = open[:len(open)-1]
+			r.matchingPDI[j] = i
+			r.matchingInitiator[i] = j
+		case t == xbidi.B:
+			open = open[:0]
+		}
+	}
+}
+
+// firstStrong returns the level of the first strong character between start
+// and end, skipping any isolates, as rules P2 and P3 do.
+func (r *resolver) firstStrong(start, end int) (level int8, found bool) {
+	for i := start; i < end; i++ {
+		switch t := r.initial[i]; {
+		case t == xbidi.L:
+			return 0, true
+		case t == xbidi.R || t == xbidi.AL:
+			return 1, true
+		case isIsolateInitiator(t):
+			i = r.matchingPDI[i]
+		case t == xbidi.B:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+func (r *resolver) resolve() {
+	if len(r.initial) == 0 {
+		return
+	}
+
+	r.explicitLevels()
+	for _, seq := range r.isolatingRunSequences() {
+		seq.resolveWeakTypes()
+		seq.resolveBrackets()
+		seq.resolveNeutralTypes()
+		seq.resolveImplicitLevels()
+	}
+	r.levelRemovedCharacters()
+}
+
+// status is an entry of the directional status stack of rules X1 through X8.
+type status struct {
+	level    int8
+	override xbidi.Class // L, R, or ON when there is no override
+	isolate  bool
+}
+
+// explicitLevels applies rules X1 through X8.
+func (r *resolver) explicitLevels() {
+	stack := make([]status, 1, maxDepth+2)
+	stack[0] = status{level: r.level, override: xbidi.ON}
+	var overflowIsolates, overflowEmbeddings, validIsolates int
+
+	for i, t := range r.initial {
+		last := stack[len(stack)-1]
+		switch t {
+		case xbidi.RLE, xbidi.LRE, xbidi.RLO, xbidi.LRO, xbidi.RLI, xbidi.LRI, xbidi.FSI:
+			isolate := isIsolateInitiator(t)
+			rtl := t == xbidi.RLE || t == xbidi.RLO || t == xbidi.RLI
+			if t == xbidi.FSI {
+				l, _ := r.firstStrong(i+1, r.matchingPDI[i])
+				rtl = l == 1
+			}
+
+			if isolate {
+				r.levels[i] = last.level
+				if last.override != xbidi.ON {
+					r.types[i] = last.override
+				}
+			}
+
+			level := (last.level + 2) &^ 1
+			if rtl {
+				level = (last.level + 1) | 1
+			}
+			if level <= maxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				override := xbidi.ON
+				switch t {
+				case xbidi.LRO:
+					override = xbidi.L
+				case xbidi.RLO:
+					override = xbidi.R
+				}
+				if isolate {
+					validIsolates++
+				} else {
+					r.levels[i] = level
+				}
+				stack = append(stack, status{level: level, override: override, isolate: isolate})
+			} else if isolate {
+				overflowIsolates++
+			} else if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+
+		case xbidi.PDI:
+			switch {
+			case overflowIsolates > 0:
+				overflowIsolates--
+			case validIsolates > 0:
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			last = stack[len(stack)-1]
+			r.levels[i] = last.level
+			if last.override != xbidi.ON {
+				r.types[i] = last.override
+			}
+
+		case xbidi.PDF:
+			r.levels[i] = last.level
+			switch {
+			case overflowIsolates > 0:
+			case overflowEmbeddings > 0:
+				overflowEmbeddings--
+			case !last.isolate && len(stack) >= 2:
+				stack = stack[:len(stack)-1]
+			}
+
+		case xbidi.B:
+			// Rule X8: the end of a paragraph ends everything in it.
+			stack = stack[:1]
+			overflowIsolates, overflowEmbeddings, validIsolates = 0, 0, 0
+			r.levels[i] = r.level
+
+		default:
+			r.levels[i] = last.level
+			if last.override != xbidi.ON {
+				r.types[i] = last.override
+			}
+		}
+	}
+}
+
+// levelRuns returns the runs of characters at the same level, leaving out
+// those removed by rule X9, as rule BD7 describes.
+func (r *resolver) levelRuns() [][]int {
+	var runs [][]int
+	var run []int
+	level := int8(-1)
+	for i, t := range r.initial {
+		if isRemovedByX9(t) {
+			continue
+		}
+		if r.levels[i] != level && run != nil {
+			runs = append(runs, run)
+			run = nil
+		}
+		level = r.levels[i]
+		run = append(run, i)
+	}
+	if run != nil {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// isolatingRunSequences returns the isolating run sequences of the paragraph,
+// as rule X10 describes. Level runs that end with an isolate initiator are
+// joined with the run that starts with its matching PDI.
+func (r *resolver) isolatingRunSequences() []*sequence {
+	runs := r.levelRuns()
+	runOf := make([]int, len(r.initial))
+	for i, run := range runs {
+		for _, j := range run {
+			runOf[j] = i
+		}
+	}
+
+	var sequences []*sequence
+	for _, run := range runs {
+		if first := run[0]; r.initial[first] == xbidi.PDI && r.matchingInitiator[first] != -1 {
+			continue
+		}
+
+		var indexes []int
+		for {
+			indexes = append(indexes, run...)
+			last := indexes[len(indexes)-1]
+			if !isIsolateInitiator(r.initial[last]) || r.matchingPDI[last] == len(r.initial) {
+				break
+			}
+			run = runs[runOf[r.matchingPDI[last]]]
+		}
+		sequences = append(sequences, r.sequence(indexes))
+	}
+	return sequences
+}
+
+func (r *resolver) sequence(indexes []int) *sequence {
+	s := &sequence{
+		r:       r,
+		indexes: indexes,
+		types:   make([]xbidi.Class, len(indexes)),
+		level:   r.levels[indexes[0]],
+	}
+	for i, j := range indexes {
+		s.types[i] = r.types[j]
+	}
+
+	prev := r.level
+	for i := indexes[0] - 1; i >= 0; i-- {
+		if !isRemovedByX9(r.initial[i]) {
+			prev = r.levels[i]
+			break
+		}
+	}
+
+	next := r.level
+	if last := indexes[len(indexes)-1]; !isIsolateInitiator(r.initial[last]) {
+		for i := last + 1; i < len(r.initial); i++ {
+			if !isRemovedByX9(r.initial[i]) {
+				next = r.levels[i]
+				break
+			}
+		}
+	}
+
+	s.sos = typeForLevel(max(prev, s.level))
+	s.eos = typeForLevel(max(next, s.level))
+	return s
+}
+
+// levelRemovedCharacters gives the characters removed by rule X9 the level
+// of the character before them, so that they do not break up a run when a
+// line is reordered.
+func (r *resolver) levelRemovedCharacters() {
+	for i, t := range r.initial {
+		if !isRemovedByX9(t) {
+			continue
+		}
+		if i == 0 {
+			r.levels[i] = r.level
+		} else {
+			r.levels[i] = r.levels[i-1]
+		}
+	}
+}
+
+// sequence is an isolating run sequence, the unit that rules W1 through I2
+// are applied to.
+type sequence struct {
+	r        *resolver
+	indexes  []int
+	types    []xbidi.Class
+	level    int8
+	sos, eos xbidi.Class
+}
+
+func (s *sequence) initial(i int) xbidi.Class {
+	return s.r.initial[s.indexes[i]]
+}
+
+// resolveWeakTypes applies rules W1 through W7.
+func (s *sequence) resolveWeakTypes() {
+	// W1: nonspacing marks take the type of the character before them.
+	prev := s.sos
+	for i, t := range s.types {
+		if t == xbidi.NSM {
+			s.types[i] = prev
+			continue
+		}
+		prev = t
+		if isIsolateInitiator(t) || t == xbidi.PDI {
+			prev = xbidi.ON
+		}
+	}
+
+	// W2: European numbers after Arabic letters are Arabic numbers.
+	// W3: Arabic letters are right to left.
+	strong := s.sos
+	for i, t := range s.types {
+		switch t {
+		case xbidi.L, xbidi.R:
+			strong = t
+		case xbidi.AL:
+			strong = t
+			s.types[i] = xbidi.R
+		case xbidi.EN:
+			if strong == xbidi.AL {
+				s.types[i] = xbidi.AN
+			}
+		}
+	}
+
+	// W4: a single separator between two numbers of the same kind joins
+	// them.
+	for i := 1; i < len(s.types)-1; i++ {
+		t, before, after := s.types[i], s.types[i-1], s.types[i+1]
+		switch {
+		case t == xbidi.ES && before == xbidi.EN && after == xbidi.EN:
+			s.types[i] = xbidi.EN
+		case t == xbidi.CS && before == xbidi.EN && after == xbidi.EN:
+			s.types[i] = xbidi.EN
+		case t == xbidi.CS && before == xbidi.AN && after == xbidi.AN:
+			s.types[i] = xbidi.AN
+		}
+	}
+
+	// W5: terminators next to European numbers are European numbers.
+	for i := 0; i < len(s.types); i++ {
+		if s.types[i] != xbidi.ET {
+			continue
+		}
+		end := s.runLimit(i, xbidi.ET)
+		if (i > 0 && s.types[i-1] == xbidi.EN) || (end < len(s.types) && s.types[end] == xbidi.EN) {
+			setTypes(s.types[i:end], xbidi.EN)
+		}
+		i = end
+	}
+
+	// W6: remaining separators and terminators are neutral.
+	for i, t := range s.types {
+		if t == xbidi.ES || t == xbidi.ET || t == xbidi.CS {
+			s.types[i] = xbidi.ON
+		}
+	}
+
+	// W7: European numbers in left to right text are left to right.
+	strong = s.sos
+	for i, t := range s.types {
+		switch t {
+		case xbidi.L, xbidi.R:
+			strong = t
+		case xbidi.EN:
+			if strong == xbidi.L {
+				s.types[i] = xbidi.L
+			}
+		}
+	}
+}
+
+// resolveBrackets applies rule N0, which gives both brackets of a pair the
+// direction of the text they enclose.
+func (s *sequence) resolveBrackets() {
+	embedding := typeForLevel(s.level)
+	for _, pair := range s.bracketPairs() {
+		open, close := pair[0], pair[1]
+
+		inside := xbidi.ON
+		for i := open + 1; i < close; i++ {
+			if d := strongDir(s.types[i]); d != xbidi.ON {
+				inside = d
+				if d == embedding {
+					break
+				}
+			}
+		}
+
+		switch inside {
+		case xbidi.ON:
+			continue
+		case embedding:
+		default:
+			// The brackets take the opposite direction only when the
+			// text before them has it too.
+			before := s.sos
+			for i := open - 1; i >= 0; i-- {
+				if d := strongDir(s.types[i]); d != xbidi.ON {
+					before = d
+					break
+				}
+			}
+			if before != inside {
+				inside = embedding
+			}
+		}
+
+		s.setBracket(open, inside)
+		s.setBracket(close, inside)
+	}
+}
+
+// setBracket sets the type of the bracket at i, along with any nonspacing
+// marks that follow it.
+func (s *sequence) setBracket(i int, t xbidi.Class) {
+	s.types[i] = t
+	for i++; i < len(s.types) && s.initial(i) == xbidi.NSM; i++ {
+		s.types[i] = t
+	}
+}
+
+// bracketPairs returns the positions of the bracket pairs in the sequence,
+// in order of their opening brackets, as rule BD16 describes.
+func (s *sequence) bracketPairs() [][2]int {
+	type opener struct {
+		closing rune
+		pos     int
+	}
+	var (
+		open  []opener
+		pairs [][2]int
+	)
+	for i, j := range s.indexes {
+		if s.types[i] != xbidi.ON {
+			continue
+		}
+		r := canonicalBracket(s.r.runes[j])
+		if closing, ok := brackets[r]; ok {
+			if len(open) == maxBrackets {
+				break
+			}
+			open = append(open, opener{closing: closing, pos: i})
+			continue
+		}
+		if !closers[r] {
+			continue
+		}
+		for k := len(open) - 1; k >= 0; k-- {
+			if open[k].closing == r {
+				pairs = append(pairs, [2]int{open[k].pos, i})
+				open = open[:k]
+				break
+			}
+		}
+	}
+	slices.SortFunc(pairs, func(a, b [2]int) int { return a[0] - b[0] })
+	return pairs
+}
+
+// strongDir returns the direction a type counts as for rules N0 and N1,
+// where numbers count as right to left, or ON for neutral types.
+func strongDir(t xbidi.Class) xbidi.Class {
+	switch t {
+	case xbidi.L:
+		return xbidi.L
+	case xbidi.R, xbidi.EN, xbidi.AN:
+		return xbidi.R
+	}
+	return xbidi.ON
+}
+
+func isNeutral(t xbidi.Class) bool {
+	switch t {
+	case xbidi.B, xbidi.S, xbidi.WS, xbidi.ON, xbidi.LRI, xbidi.RLI, xbidi.FSI, xbidi.PDI:
+		return true
+	}
+	return false
+}
+
+// resolveNeutralTypes applies rules N1 and N2.
+func (s *sequence) resolveNeutralTypes() {
+	for i := 0; i < len(s.types); i++ {
+		if !isNeutral(s.types[i]) {
+			continue
+		}
+		end := i
+		for end < len(s.types) && isNeutral(s.types[end]) {
+			end++
+		}
+
+		before, after := s.sos, s.eos
+		if i > 0 {
+			before = strongDir(s.types[i-1])
+		}
+		if end < len(s.types) {
+			after = strongDir(s.types[end])
+		}
+
+		// N1: neutrals between text of the same direction take it.
+		// N2: any others take the embedding direction.
+		t := typeForLevel(s.level)
+		if before == after {
+			t = before
+		}
+		setTypes(s.types[i:end], t)
+		i = end
+	}
+}
+
+// resolveImplicitLevels applies rules I1 and I2, and stores the resolved
+// levels.
+func (s *sequence) resolveImplicitLevels() {
+	for i, j := range s.indexes {
+		level := s.level
+		switch t := s.types[i]; {
+		case level&1 == 0 && t == xbidi.R:
+			level++
+		case level&1 == 0 && (t == xbidi.AN || t == xbidi.EN):
+			level += 2
+		case level&1 == 1 && t != xbidi.R:
+			level++
+		}
+		s.r.levels[j] = level
+		s.r.types[j] = s.types[i]
+	}
+}
+
+// runLimit returns the end of the run of characters of type t starting at i.
+func (s *sequence) runLimit(i int, t xbidi.Class) int {
+	for i < len(s.types) && s.types[i] == t {
+		i++
+	}
+	return i
+}
+
+func setTypes(types []xbidi.Class, t xbidi.Class) {
+	for i := range types {
+		types[i] = t
+	}
+}
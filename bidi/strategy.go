@@ -0,0 +1,175 @@
+package bidi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Strategy is a way of marking RTL text so that whatever shows it knows its
+// direction. Terminals need directional marks, browsers understand HTML, and
+// data pipelines usually want the text left alone.
+type Strategy int
+
+const (
+	// Marks puts an RLM (U+200F) before each RTL sequence and an LRM (U+200E)
+	// after it. This is what String does.
+	Marks Strategy = iota
+
+	// Isolates puts each RTL sequence between an FSI (U+2068) and a PDI
+	// (U+2069), so that it does not affect the text around it.
+	Isolates
+
+	// HTML wraps each RTL sequence in a <span dir="rtl"> element.
+	HTML
+
+	// Raw leaves the text as it is.
+	Raw
+)
+
+// ErrUnknownStrategy is returned by ParseStrategy for names it does not know.
+var ErrUnknownStrategy = errors.New("unknown bidi strategy")
+
+var strategies = map[string]Strategy{
+	"marks":    Marks,
+	"isolates": Isolates,
+	"html":     HTML,
+	"raw":      Raw,
+}
+
+// ParseStrategy returns the strategy with the given name, one of "marks",
+// "isolates", "html" or "raw".
+func ParseStrategy(name string) (Strategy, error) {
+	if s, ok := strategies[strings.ToLower(name)]; ok {
+		return s, nil
+	}
+	return Marks, fmt.Errorf("%w: %q", ErrUnknownStrategy, name)
+}
+
+func (s Strategy) String() string {
+	switch s {
+	case Isolates:
+		return "isolates"
+	case HTML:
+		return "html"
+	case Raw:
+		return "raw"
+	}
+	return "marks"
+}
+
+// Apply marks each RTL sequence of text with the strategy.
+func (s Strategy) Apply(text string) string {
+	return s.Convert(wrapRTL(text))
+}
+
+// Convert marks each sequence of text that is already marked with an RLM and
+// an LRM, as String marks them, with the strategy instead. Text outside of
+// such sequences is left as it is.
+func (s Strategy) Convert(text string) string {
+	if s == Marks {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for {
+		start := strings.IndexRune(text, '\u200F')
+		if start < 0 {
+			break
+		}
+		b.WriteString(text[:start])
+
+		span := text[start+len("\u200F"):]
+		end := strings.IndexRune(span, '\u200E')
+		if end < 0 {
+			end = len(span)
+			text = ""
+		} else {
+			text = span[end+len("\u200E"):]
+		}
+		b.WriteString(s.wrap(span[:end]))
+	}
+	b.WriteString(text)
+	return b.String()
+}
+
+func (s Strategy) wrap(text string) string {
+	switch s {
+	case Marks:
+		return "\u200F" + text + "\u200E"
+	case Isolates:
+		return "\u2068" + text + "\u2069"
+	case HTML:
+		return `<span dir="rtl">` + text + "</span>"
+	}
+	return text
+}
+
+// ConvertJSON converts each string of a JSON document, such as one holding a
+// marshaled String, as Convert does. The rest of the document is left as it
+// is.
+func (s Strategy) ConvertJSON(data []byte) ([]byte, error) {
+	if s == Marks {
+		return data, nil
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	for i := 0; i < len(data); {
+		if data[i] != '"' {
+			out.WriteByte(data[i])
+			i++
+			continue
+		}
+
+		end := stringEnd(data, i)
+		if end < 0 {
+			return nil, errors.New("bidi: unterminated string in JSON")
+		}
+		literal := data[i:end]
+		i = end
+		if !bytes.Contains(literal, []byte("\u200F")) && !bytes.Contains(bytes.ToLower(literal), []byte(`\u200f`)) {
+			out.Write(literal)
+			continue
+		}
+
+		var text string
+		if err := json.Unmarshal(literal, &text); err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(&out)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(s.Convert(text)); err != nil {
+			return nil, err
+		}
+		out.Truncate(out.Len() - 1) // Encode ends with a newline
+	}
+	return out.Bytes(), nil
+}
+
+// MarshalJSON returns the JSON encoding of v, with the RTL text of each String
+// in it marked with the strategy s.
+func MarshalJSON(v any, s Strategy) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return s.ConvertJSON(data)
+}
+
+// stringEnd returns the offset just past the end of the JSON string starting
+// at data[start], or -1 when it has no end.
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
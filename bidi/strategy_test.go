@@ -0,0 +1,138 @@
+package bidi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategy_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		input    string
+		expected string
+	}{
+		{"marks", Marks, "Hello שלום", "Hello \u200Fשלום\u200E"},
+		{"isolates", Isolates, "Hello שלום", "Hello \u2068שלום\u2069"},
+		{"html", HTML, "Hello שלום", `Hello <span dir="rtl">שלום</span>`},
+		{"raw", Raw, "Hello שלום", "Hello שלום"},
+		{"LTR only", HTML, "Hello World", "Hello World"},
+		{"several sequences", Isolates, "שלום Hello עולם", "\u2068שלום\u2069 Hello \u2068עולם\u2069"},
+		{"already marked", HTML, "\u200Fשלום\u200E", `<span dir="rtl">שלום</span>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.strategy.Apply(tt.input))
+			assert.Equal(t, tt.expected, String(tt.input).Format(tt.strategy))
+		})
+	}
+}
+
+func TestStrategy_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		input    string
+		expected string
+	}{
+		{"marks left alone", Marks, "a \u200Fשלום\u200E b", "a \u200Fשלום\u200E b"},
+		{"unmarked text left alone", Isolates, "a שלום b", "a שלום b"},
+		{"marked sequence", Isolates, "a \u200Fשלום\u200E b", "a \u2068שלום\u2069 b"},
+		{"unterminated sequence", HTML, "a \u200Fשלום", `a <span dir="rtl">שלום</span>`},
+		{"marks removed", Raw, "\u200Fשלום\u200E \u200Fעולם\u200E", "שלום עולם"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.strategy.Convert(tt.input))
+		})
+	}
+}
+
+func TestStrategy_ConvertJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		input    string
+		expected string
+	}{
+		{
+			name:     "marks left alone",
+			strategy: Marks,
+			input:    "{\"he\":\"\u200Fשלום\u200E\"}",
+			expected: "{\"he\":\"\u200Fשלום\u200E\"}",
+		},
+		{
+			name:     "html",
+			strategy: HTML,
+			input:    "{\"he\":\"\u200Fשלום\u200E\",\"en\":\"Hello\"}",
+			expected: `{"he":"<span dir=\"rtl\">שלום</span>","en":"Hello"}`,
+		},
+		{
+			name:     "escaped marks",
+			strategy: Raw,
+			input:    `["\u200Fשלום\u200E", "a \"quoted\" word"]`,
+			expected: `["שלום", "a \"quoted\" word"]`,
+		},
+		{
+			name:     "indentation kept",
+			strategy: Isolates,
+			input:    "{\n  \"he\": \"\u200Fשלום\u200E\"\n}",
+			expected: "{\n  \"he\": \"\u2068שלום\u2069\"\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.strategy.ConvertJSON([]byte(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(actual))
+		})
+	}
+
+	t.Run("unterminated string", func(t *testing.T) {
+		_, err := Raw.ConvertJSON([]byte("{\"he\":\"\u200Fשלום"))
+		assert.Error(t, err)
+	})
+}
+
+func TestMarshalJSON(t *testing.T) {
+	v := struct {
+		Title   string `json:"title"`
+		HeTitle String `json:"heTitle"`
+	}{"Genesis", "בראשית"}
+
+	data, err := MarshalJSON(v, Raw)
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"Genesis","heTitle":"בראשית"}`, string(data))
+
+	data, err = MarshalJSON(v, Marks)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"title\":\"Genesis\",\"heTitle\":\"\u200Fבראשית\u200E\"}", string(data))
+}
+
+func TestParseStrategy(t *testing.T) {
+	for _, s := range []Strategy{Marks, Isolates, HTML, Raw} {
+		parsed, err := ParseStrategy(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+
+	parsed, err := ParseStrategy("HTML")
+	require.NoError(t, err)
+	assert.Equal(t, HTML, parsed)
+
+	_, err = ParseStrategy("visual")
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+}
+
+func ExampleMarshalJSON() {
+	data, _ := MarshalJSON(map[string]String{"he": "בראשית 1:1"}, HTML)
+	fmt.Println(string(data))
+	// Output:
+	// {"he":"<span dir=\"rtl\">בראשית</span> 1:1"}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"unicode"
+
+	xbidi "golang.org/x/text/unicode/bidi"
 )
 
 // String is a custom string type that provides automatic bidirectional text
@@ -17,9 +19,16 @@ func (s String) String() string {
 	return wrapRTL(string(s))
 }
 
+// Format returns the string with its RTL text marked with the strategy st,
+// for output that should not hold directional marks, such as HTML.
+func (s String) Format(st Strategy) string {
+	return st.Apply(string(s))
+}
+
 // MarshalJSON implements json.Marshaler, applying RTL wrapping when marshaling
 // to JSON. This ensures that RTL text is properly marked for bidirectional
-// display when serialized.
+// display when serialized. The MarshalJSON function marks it with any other
+// Strategy.
 func (s String) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.String())
 }
@@ -74,11 +83,11 @@ func wrapRTL(str string) string {
 			// This is a malformed sequence, treat the LRM as regular text
 			buf.WriteRune(runes[i])
 			i++
-		} else if isRTL(runes[i]) {
+		} else if startsRTL(runes[i]) {
 			buf.WriteRune('\u200F') // RLM
 			// Find the end of the RTL sequence, including spaces between RTL words
 			start := i
-			for i < len(runes) && (isRTL(runes[i]) || unicode.IsSpace(runes[i]) || unicode.Is(unicode.Punct, runes[i])) {
+			for i < len(runes) && (startsRTL(runes[i]) || isMark(runes[i]) || unicode.IsSpace(runes[i]) || unicode.Is(unicode.Punct, runes[i])) {
 				// If we hit a space, check if it's followed by more RTL text
 				if unicode.IsSpace(runes[i]) || unicode.Is(unicode.Punct, runes[i]) {
 					// Look ahead to see if there's more RTL text after spaces
@@ -86,7 +95,7 @@ func wrapRTL(str string) string {
 					for j < len(runes) && (unicode.IsSpace(runes[j]) || unicode.Is(unicode.Punct, runes[j])) {
 						j++
 					}
-					if j < len(runes) && isRTL(runes[j]) {
+					if j < len(runes) && startsRTL(runes[j]) {
 						i = j    // include the spaces and continue with RTL text
 						continue // Continue the loop to process the RTL character at position i
 					} else {
@@ -95,7 +104,7 @@ func wrapRTL(str string) string {
 						// For punctuation, only include it if it's RTL punctuation
 						if unicode.IsSpace(runes[i]) {
 							break
-						} else if isRTL(runes[i]) {
+						} else if startsRTL(runes[i]) {
 							// Include RTL punctuation in RTL sequence
 							i++
 							break
@@ -124,3 +133,10 @@ func wrapRTL(str string) string {
 	}
 	return buf.String()
 }
+
+// startsRTL reports whether r begins or carries on an RTL sequence. Arabic
+// digits belong to the Arabic text around them. Combining marks, such as
+// niqqud, carry on a sequence but never begin one.
+func startsRTL(r rune) bool {
+	return isRTL(r) || class(r) == xbidi.AN
+}
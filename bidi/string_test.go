@@ -421,6 +421,21 @@ func TestWrapRTL(t *testing.T) {
 			input:    "Hello שלום World",
 			expected: "Hello \u200Fשלום\u200E World",
 		},
+		{
+			name:     "Hebrew with niqqud",
+			input:    "Read בְּרֵאשִׁית today",
+			expected: "Read \u200Fבְּרֵאשִׁית\u200E today",
+		},
+		{
+			name:     "Arabic with Arabic digits",
+			input:    "مرحبا ١٢٣",
+			expected: "\u200Fمرحبا ١٢٣\u200E",
+		},
+		{
+			name:     "Syriac",
+			input:    "Peshitta ܫܠܡܐ",
+			expected: "Peshitta \u200Fܫܠܡܐ\u200E",
+		},
 		{
 			name:     "Multiple RTL sequences",
 			input:    "Hello שלום World مرحبا Test",
@@ -537,6 +552,36 @@ func TestIsRTL(t *testing.T) {
 			input:    '،',
 			expected: false,
 		},
+		{
+			name:     "Hebrew point - not a letter",
+			input:    '\u05B0', // sheva
+			expected: false,
+		},
+		{
+			name:     "Syriac letter",
+			input:    'ܐ',
+			expected: true,
+		},
+		{
+			name:     "Thaana letter",
+			input:    'ދ',
+			expected: true,
+		},
+		{
+			name:     "N'Ko letter",
+			input:    'ߒ',
+			expected: true,
+		},
+		{
+			name:     "Arabic presentation form",
+			input:    '\uFEE3', // meem, medial form
+			expected: true,
+		},
+		{
+			name:     "Hebrew presentation form",
+			input:    '\uFB2A', // shin with shin dot
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
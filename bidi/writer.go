@@ -3,77 +3,213 @@ package bidi
 import (
 	"bytes"
 	"io"
-	"unicode"
+	"strings"
+	"unicode/utf8"
+
+	xbidi "golang.org/x/text/unicode/bidi"
 )
 
-// Writer is an io.Writer that processes bidirectional text by reversing
-// RTL sequences marked with Unicode directional markers. It can also
-// optionally reverse all RTL text when the flipRTL flag is enabled.
+// Writer is an io.Writer that lays out bidirectional text in visual order,
+// for terminals that show characters in the order they are given. RTL
+// sequences marked with Unicode directional markers are laid out right to
+// left, and when the flipRTL flag is enabled, so is all other RTL text.
+//
+// Text is held back until it can be laid out, so that it may be written in
+// pieces of any size: a character split across two writes, or a marked
+// sequence whose closing marker has not been written yet, is kept until the
+// rest of it arrives. With flipRTL enabled, text is written a line at a time.
+// Flush or Close must be called once writing is done to write out whatever
+// is still held back.
 type Writer struct {
 	w       io.Writer
 	flipRTL bool // optional flag to flip text even without markers
+
+	// pending is the text that has been written but not laid out yet, and
+	// scanned is how much of it is known to hold nothing that would let more
+	// of it be laid out, so that each write looks only at what it added.
+	pending []byte
+	scanned int
 }
 
 // NewWriter creates a new Writer that wraps the provided io.Writer.
-// If flipRTL is true, all RTL text will be reversed even without directional markers.
-// If false, only text between RLM/LRM markers will be reversed.
+// If flipRTL is true, all RTL text will be laid out even without directional markers.
+// If false, only text between RLM/LRM markers will be laid out.
 func NewWriter(w io.Writer, flipRTL bool) *Writer {
 	return &Writer{w: w, flipRTL: flipRTL}
 }
 
-// Write implements io.Writer, processing bidirectional text by reversing
-// RTL sequences. Text between RLM (U+200F) and LRM (U+200E) markers
-// is reversed, and if flipRTL is enabled, all RTL text is reversed.
+// Write implements io.Writer, laying out bidirectional text in visual order.
+// Text between RLM (U+200F) and LRM (U+200E) markers is laid out right to
+// left. If flipRTL is enabled, each line is instead laid out in full by the
+// Unicode Bidirectional Algorithm, in the direction of its first strong
+// character, and directional marks are dropped from the output.
 func (bw *Writer) Write(p []byte) (int, error) {
 	// Check if the underlying writer is nil
 	if bw.w == nil {
 		return len(p), io.ErrClosedPipe
 	}
 
-	// Convert to runes
-	runes := []rune(string(p))
-	var out bytes.Buffer
-
-	i := 0
-	for i < len(runes) {
-		switch runes[i] {
-		case '\u200F': // RLM start
-			start := i + 1
-			end := start
-			for end < len(runes) && runes[end] != '\u200E' { // until LRM
-				end++
-			}
-			// flip the text between RLM and LRM
-			for j := end - 1; j >= start; j-- {
-				out.WriteRune(runes[j])
-			}
-			i = end + 1 // skip past LRM
-		default:
-			// optionally flip all RTL text if flag is set
-			if bw.flipRTL && isRTL(runes[i]) {
-				start := i
-				for i < len(runes) && isRTL(runes[i]) {
-					i++
-				}
-				for j := i - 1; j >= start; j-- {
-					out.WriteRune(runes[j])
-				}
-			} else {
-				out.WriteRune(runes[i])
-				i++
+	bw.pending = append(bw.pending, p...)
+	return len(p), bw.emit(false)
+}
+
+// Flush lays out and writes any text that is still held back, such as the
+// last line when it has no trailing newline, or a marked sequence that was
+// never closed.
+func (bw *Writer) Flush() error {
+	if bw.w == nil {
+		return io.ErrClosedPipe
+	}
+	return bw.emit(true)
+}
+
+// Close flushes the Writer. It does not close the underlying writer.
+func (bw *Writer) Close() error {
+	return bw.Flush()
+}
+
+// emit lays out as much of the pending text as it can and writes it out. When
+// final is set, all of it is laid out.
+func (bw *Writer) emit(final bool) error {
+	if !final && !bw.ready() {
+		return nil
+	}
+
+	var out, rest string
+	if bw.flipRTL {
+		out, rest = visualLines(string(bw.pending), final)
+	} else {
+		out, rest = visualMarked(string(bw.pending), final)
+	}
+	bw.pending = append(bw.pending[:0], rest...)
+	bw.scanned = len(bw.pending)
+	if out == "" {
+		return nil
+	}
+
+	n, err := io.WriteString(bw.w, out)
+	if n != len(out) && err == nil {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// ready reports whether some of the pending text can be laid out, looking
+// only at the text written since it was last scanned. With flipRTL enabled,
+// that takes the end of a line. Otherwise it takes anything but a marked
+// sequence still waiting for its LRM.
+func (bw *Writer) ready() bool {
+	from := bw.scanned
+	bw.scanned = len(bw.pending)
+	if bw.flipRTL {
+		// Start from the beginning of a character split across writes.
+		for from > 0 && from < len(bw.pending) && !utf8.RuneStart(bw.pending[from]) {
+			from--
+		}
+		return bytes.ContainsFunc(bw.pending[from:], func(r rune) bool {
+			return class(r) == xbidi.B
+		})
+	}
+	if !bytes.HasPrefix(bw.pending, rlm) {
+		return true
+	}
+	// Start early enough to find an LRM split across writes.
+	from = max(from-len(lrm)+1, len(rlm))
+	return bytes.Contains(bw.pending[min(from, len(bw.pending)):], lrm)
+}
+
+// rlm and lrm are the encodings of the markers around RTL sequences.
+var (
+	rlm = []byte("\u200F")
+	lrm = []byte("\u200E")
+)
+
+// visualLines lays out each complete line of s, and returns the rest of it,
+// unless final is set.
+func visualLines(s string, final bool) (out, rest string) {
+	end := len(s)
+	if !final {
+		end = 0
+		for i, r := range s {
+			if class(r) == xbidi.B {
+				end = i + utf8.RuneLen(r)
 			}
 		}
 	}
+	return stripMarks(Visual(s[:end], Auto)), s[end:]
+}
 
-	n, err := bw.w.Write(out.Bytes())
-	if n != len(out.Bytes()) && err == nil {
-		err = io.ErrShortWrite
+// visualMarked lays out each span of s that starts with an RLM, up to the
+// next LRM, right to left. The markers around each span are dropped. Unless
+// final is set, a span with no LRM yet and an incomplete character at the end
+// of s are returned as the rest of it; otherwise a span runs to the end of s.
+func visualMarked(s string, final bool) (string, string) {
+	var out strings.Builder
+	for {
+		start := strings.IndexRune(s, '\u200F')
+		if start < 0 {
+			break
+		}
+		out.WriteString(s[:start])
+
+		span := s[start+len("\u200F"):]
+		end := strings.IndexRune(span, '\u200E')
+		switch {
+		case end >= 0:
+			out.WriteString(Visual(span[:end], RightToLeft))
+			s = span[end+len("\u200E"):]
+			continue
+		case final:
+			out.WriteString(Visual(span, RightToLeft))
+			return out.String(), ""
+		}
+		return out.String(), s[start:]
 	}
-	return len(p), err
+
+	end := len(s)
+	if !final {
+		end = completeLen(s)
+	}
+	out.WriteString(s[:end])
+	return out.String(), s[end:]
+}
+
+// completeLen returns the length of s without an incomplete UTF-8 encoded
+// character at its end.
+func completeLen(s string) int {
+	for i := len(s) - 1; i >= 0 && i > len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			if utf8.FullRuneInString(s[i:]) {
+				return len(s)
+			}
+			return i
+		}
+	}
+	return len(s)
+}
+
+// stripMarks removes directional marks and explicit directional formatting
+// characters, which have no use once text is in visual order.
+func stripMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200E', '\u200F', '\u061C':
+			return -1
+		}
+		switch class(r) {
+		case xbidi.LRE, xbidi.RLE, xbidi.LRO, xbidi.RLO, xbidi.PDF,
+			xbidi.LRI, xbidi.RLI, xbidi.FSI, xbidi.PDI:
+			return -1
+		}
+		return r
+	}, s)
 }
 
-// isRTL detects if a rune belongs to a right-to-left script.
-// Currently supports Hebrew and Arabic scripts.
+// isRTL detects if a rune is written right to left, as letters of the
+// Hebrew, Arabic, Syriac, Thaana and N'Ko scripts, and their presentation
+// forms, are. It goes by the rune's bidi class, R or AL, rather than its
+// script, so it covers every RTL script Unicode knows of.
 func isRTL(r rune) bool {
-	return unicode.In(r, unicode.Hebrew, unicode.Arabic)
+	t := class(r)
+	return t == xbidi.R || t == xbidi.AL
 }
@@ -0,0 +1,162 @@
+package bidi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Write_HoldsBackIncompleteText(t *testing.T) {
+	t.Run("character split across writes", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, false)
+
+		input := []byte("Hello ש")
+		_, err := writer.Write(input[:len(input)-1])
+		require.NoError(t, err)
+		assert.Equal(t, "Hello ", buf.String())
+
+		_, err = writer.Write(input[len(input)-1:])
+		require.NoError(t, err)
+		assert.Equal(t, "Hello ש", buf.String())
+	})
+
+	t.Run("marked sequence held until its LRM", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, false)
+
+		_, err := writer.Write([]byte("Hello \u200Fשל"))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello ", buf.String())
+
+		_, err = writer.Write([]byte("ום\u200E World"))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello םולש World", buf.String())
+	})
+
+	t.Run("lines written as they are completed", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, true)
+
+		_, err := writer.Write([]byte("פרק 12\nפרק"))
+		require.NoError(t, err)
+		assert.Equal(t, "12 קרפ\n", buf.String())
+
+		_, err = writer.Write([]byte(" 13"))
+		require.NoError(t, err)
+		assert.Equal(t, "12 קרפ\n", buf.String())
+
+		require.NoError(t, writer.Close())
+		assert.Equal(t, "12 קרפ\n13 קרפ", buf.String())
+	})
+
+	t.Run("flush with nothing held back", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, true)
+
+		require.NoError(t, writer.Flush())
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("flush with nil writer", func(t *testing.T) {
+		assert.Equal(t, io.ErrClosedPipe, NewWriter(nil, true).Flush())
+	})
+}
+
+// writeSplit writes each part of input to a new Writer, then flushes it.
+func writeSplit(t *testing.T, flipRTL bool, parts ...string) string {
+	var buf bytes.Buffer
+	writer := NewWriter(&buf, flipRTL)
+	for _, part := range parts {
+		n, err := writer.Write([]byte(part))
+		require.NoError(t, err)
+		require.Equal(t, len(part), n)
+	}
+	require.NoError(t, writer.Flush())
+	return buf.String()
+}
+
+// FuzzWriter_Split checks that splitting the input across two writes, at
+// every offset, gives the same output as writing it all at once.
+func FuzzWriter_Split(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"Hello World",
+		"שלום עולם",
+		"פרק 12 (בראשית)\nSee בראשית 1:1\r\n",
+		"Hello \u200Fשלום\u200E World \u200Fمرحبا\u200E",
+		"\u200Fשלום\nעולם",
+		"\u200F\u200Fשל\u200Fום\u200E\u200E",
+		"\u2067בראשית\u2069 1:1\u2029מרחבא",
+		"\xe2\x80 invalid \xd7",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, flipRTL := range []bool{false, true} {
+			expected := writeSplit(t, flipRTL, input)
+			for i := range len(input) + 1 {
+				actual := writeSplit(t, flipRTL, input[:i], input[i:])
+				if actual != expected {
+					t.Fatalf("flipRTL=%v split at %d: got %q, want %q", flipRTL, i, actual, expected)
+				}
+			}
+		}
+	})
+}
+
+func TestWriter_Write_SmallWrites(t *testing.T) {
+	line := strings.Repeat("בראשית ברא אלהים את השמים ואת הארץ ", 20)
+
+	// writeChunks writes input to a new Writer n bytes at a time, and returns
+	// what it wrote before it was flushed.
+	writeChunks := func(flipRTL bool, input string, n int) string {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, flipRTL)
+		for i := 0; i < len(input); i += n {
+			_, err := writer.Write([]byte(input[i:min(i+n, len(input))]))
+			require.NoError(t, err)
+		}
+		return buf.String()
+	}
+
+	// Paragraph separators and LRMs split across writes are found, so the
+	// text before them is written without waiting for a flush.
+	for _, n := range []int{1, 2, 4, 7} {
+		input := line + "\n" + line + "\u2029" + line
+		want := Visual(line, Auto) + "\n" + Visual(line, Auto) + "\u2029"
+		assert.Equal(t, want, writeChunks(true, input, n), "flipRTL, %d byte writes", n)
+
+		input = "\u200F" + line + "\u200E 1:1 \u200F" + line + "\u200E"
+		want = Visual(line, RightToLeft) + " 1:1 " + Visual(line, RightToLeft)
+		assert.Equal(t, want, writeChunks(false, input, n), "markers, %d byte writes", n)
+	}
+}
+
+// BenchmarkWriter_Write_SmallWrites writes a long line a few bytes at a time,
+// as renderers do field by field, which should take time in proportion to
+// its length.
+func BenchmarkWriter_Write_SmallWrites(b *testing.B) {
+	line := []byte(strings.Repeat("שלום עולם ", 1600))
+	for _, flipRTL := range []bool{false, true} {
+		input := line
+		if !flipRTL {
+			input = []byte("\u200F" + string(line) + "\u200E")
+		}
+		b.Run(fmt.Sprintf("flipRTL=%v", flipRTL), func(b *testing.B) {
+			for range b.N {
+				writer := NewWriter(io.Discard, flipRTL)
+				for i := 0; i < len(input); i += 4 {
+					writer.Write(input[i:min(i+4, len(input))])
+				}
+				writer.Flush()
+			}
+		})
+	}
+}
@@ -113,7 +113,7 @@ func TestWriter_Write_WithMarkers(t *testing.T) {
 		{
 			name:     "RTL text with newlines in markers",
 			input:    "\u200Fשלום\nעולם\u200E",
-			expected: "םלוע\nםולש",
+			expected: "םולש\nםלוע",
 			flipRTL:  false,
 		},
 		{
@@ -156,6 +156,7 @@ func TestWriter_Write_WithMarkers(t *testing.T) {
 			n, err := writer.Write([]byte(tt.input))
 			require.NoError(t, err)
 			assert.Equal(t, len(tt.input), n)
+			require.NoError(t, writer.Flush())
 			assert.Equal(t, tt.expected, buf.String())
 		})
 	}
@@ -195,13 +196,13 @@ func TestWriter_Write_WithFlipRTL(t *testing.T) {
 		{
 			name:     "RTL text with spaces - flipRTL enabled",
 			input:    "שלום עולם",
-			expected: "םולש םלוע",
+			expected: "םלוע םולש",
 			flipRTL:  true,
 		},
 		{
 			name:     "RTL text with tabs - flipRTL enabled",
 			input:    "שלום\tעולם",
-			expected: "םולש\tםלוע",
+			expected: "םלוע\tםולש",
 			flipRTL:  true,
 		},
 		{
@@ -246,6 +247,24 @@ func TestWriter_Write_WithFlipRTL(t *testing.T) {
 			expected: "The word םולש means 'hello' in Hebrew, and ابحرم means 'hello' in Arabic.",
 			flipRTL:  true,
 		},
+		{
+			name:     "number inside Hebrew - flipRTL enabled",
+			input:    "פרק 12",
+			expected: "12 קרפ",
+			flipRTL:  true,
+		},
+		{
+			name:     "brackets inside Hebrew - flipRTL enabled",
+			input:    "בראשית (35)",
+			expected: "(35) תישארב",
+			flipRTL:  true,
+		},
+		{
+			name:     "directional marks dropped - flipRTL enabled",
+			input:    "Read \u200Fבראשית א\u200E today",
+			expected: "Read א תישארב today",
+			flipRTL:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -256,6 +275,7 @@ func TestWriter_Write_WithFlipRTL(t *testing.T) {
 			n, err := writer.Write([]byte(tt.input))
 			require.NoError(t, err)
 			assert.Equal(t, len(tt.input), n)
+			require.NoError(t, writer.Flush())
 			assert.Equal(t, tt.expected, buf.String())
 		})
 	}
@@ -283,13 +303,13 @@ func TestWriter_Write_MultipleWrites(t *testing.T) {
 		{
 			name:     "marker split across writes",
 			inputs:   []string{"\u200Fשל", "ום\u200E"},
-			expected: "לשום\u200E",
+			expected: "םולש",
 			flipRTL:  false,
 		},
 		{
 			name:     "RTL text split across writes with flipRTL",
 			inputs:   []string{"של", "ום"},
-			expected: "לשםו",
+			expected: "םולש",
 			flipRTL:  true,
 		},
 		{
@@ -318,6 +338,7 @@ func TestWriter_Write_MultipleWrites(t *testing.T) {
 			}
 
 			assert.Equal(t, expectedTotal, totalWritten)
+			require.NoError(t, writer.Flush())
 			assert.Equal(t, tt.expected, buf.String())
 		})
 	}
@@ -363,7 +384,7 @@ func TestWriter_Write_EdgeCases(t *testing.T) {
 		{
 			name:     "markers with LTR text inside",
 			input:    "\u200Fשלום Hello עולם\u200E",
-			expected: "םלוע olleH םולש",
+			expected: "םלוע Hello םולש",
 			flipRTL:  false,
 		},
 		{
@@ -387,7 +408,7 @@ func TestWriter_Write_EdgeCases(t *testing.T) {
 		{
 			name:     "numbers and currency with RTL",
 			input:    "Price: \u200F100₪\u200E",
-			expected: "Price: ₪001",
+			expected: "Price: 100₪",
 			flipRTL:  false,
 		},
 	}
@@ -400,6 +421,7 @@ func TestWriter_Write_EdgeCases(t *testing.T) {
 			n, err := writer.Write([]byte(tt.input))
 			require.NoError(t, err)
 			assert.Equal(t, len(tt.input), n)
+			require.NoError(t, writer.Flush())
 			assert.Equal(t, tt.expected, buf.String())
 		})
 	}
@@ -510,6 +532,7 @@ func TestWriter_IntegrationWithString(t *testing.T) {
 			n, err := writer.Write([]byte(tt.input.String()))
 			require.NoError(t, err)
 			assert.Equal(t, len(tt.input.String()), n)
+			require.NoError(t, writer.Flush())
 			assert.Equal(t, tt.expected, buf.String())
 		})
 	}
@@ -537,6 +560,7 @@ func BenchmarkWriter_Write_WithMarkers(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				buf.Reset()
 				writer.Write([]byte(tc.input))
+				writer.Flush()
 			}
 		})
 	}
@@ -563,6 +587,7 @@ func BenchmarkWriter_Write_WithFlipRTL(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				buf.Reset()
 				writer.Write([]byte(tc.input))
+				writer.Flush()
 			}
 		})
 	}
@@ -581,6 +606,7 @@ func BenchmarkWriter_Write_MultipleWrites(b *testing.B) {
 			for _, input := range inputs {
 				writer.Write([]byte(input))
 			}
+			writer.Flush()
 		}
 	})
 
@@ -595,6 +621,7 @@ func BenchmarkWriter_Write_MultipleWrites(b *testing.B) {
 			for _, input := range inputs {
 				writer.Write([]byte(input))
 			}
+			writer.Flush()
 		}
 	})
 }
@@ -33,9 +33,9 @@ type ScheduledLearning struct {
 	DisplayValue BilingualString `json:"displayValue"`
 	Description  BilingualString `json:"description"`
 
-	URL   string      `json:"url"`
-	Ref   string      `json:"ref"`
-	HeRef bidi.String `json:"heRef"`
+	URL   string      `json:"url" normalize:"-"`
+	Ref   string      `json:"ref" normalize:"-"`
+	HeRef bidi.String `json:"heRef" normalize:"-"`
 
 	Order    int    `json:"order"`
 	Category string `json:"category"`
@@ -45,6 +45,16 @@ type ScheduledLearning struct {
 	} `json:"extraDetails"`
 }
 
+// Page parses the link to the learning, which is given relative to
+// sefaria.org, e.g. "Genesis.1.1-6.8". The full link is Page().String().
+//
+// URL is left as the string the API gives, rather than parsed as the
+// learning is decoded, so that a link the parser does not understand does
+// not fail the whole calendar, and the learning is encoded again as it came.
+func (l ScheduledLearning) Page() (*Page, error) {
+	return ParseURL(l.URL)
+}
+
 func (s *CalendarService) Get(ctx context.Context, opts *CalendarGetOptions) (*LearningSchedule, error) {
 	u := s.client.BaseURL.JoinPath("/calendars")
 	if opts != nil {
@@ -138,9 +148,9 @@ type ParshaReading struct {
 type Parsha struct {
 	Title        BilingualString `json:"title"`
 	DisplayValue BilingualString `json:"displayValue"`
-	URL          string          `json:"url"`
-	Ref          string          `json:"ref"`
-	HeRef        bidi.String     `json:"heRef"`
+	URL          string          `json:"url" normalize:"-"`
+	Ref          string          `json:"ref" normalize:"-"`
+	HeRef        bidi.String     `json:"heRef" normalize:"-"`
 	Order        int             `json:"order" table:"-"`
 	Category     string          `json:"category"`
 	ExtraDetails struct {
@@ -152,8 +162,8 @@ type Parsha struct {
 type Haftorah struct {
 	Title        BilingualString `json:"title" table:"Title"`
 	DisplayValue BilingualString `json:"displayValue"`
-	URL          string          `json:"url"`
-	Ref          string          `json:"ref"`
+	URL          string          `json:"url" normalize:"-"`
+	Ref          string          `json:"ref" normalize:"-"`
 	Order        int             `json:"order"`
 	Category     string          `json:"category"`
 }
@@ -0,0 +1,312 @@
+package citation
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// address is the part of a citation that follows the title.
+type address struct {
+	sections   []string
+	toSections []string
+}
+
+// englishAddress reads an address such as "1:1", "2a:5" or "1:1-2:3" from
+// the start of s and returns it along with the number of bytes it spans.
+// Unless joined is set, the address must be separated from the title by a
+// space or comma.
+func englishAddress(s string, joined bool) (address, int, bool) {
+	i := skipSpace(s, 0)
+	if i < len(s) && s[i] == ',' {
+		i = skipSpace(s, i+1)
+	}
+	if i == 0 && !joined {
+		return address{}, 0, false
+	}
+
+	sections, n := englishSections(s[i:])
+	if n == 0 {
+		return address{}, 0, false
+	}
+	i += n
+	a := address{sections: sections}
+
+	if j, ok := skipDash(s, skipSpace(s, i)); ok {
+		j = skipSpace(s, j)
+		to, n := englishSections(s[j:])
+		if n > 0 && len(to) <= len(sections) && boundary(s[j+n:]) {
+			a.toSections = fillRange(sections, to)
+			i = j + n
+		}
+	}
+	if backwards(a) {
+		return address{}, 0, false
+	}
+
+	if !boundary(s[i:]) {
+		return address{}, 0, false
+	}
+	return a, i, true
+}
+
+// englishSections reads sections separated by colons or periods, e.g. "1:1".
+func englishSections(s string) ([]string, int) {
+	var sections []string
+	i := 0
+	for {
+		section, n := englishSection(s[i:])
+		if n == 0 {
+			break
+		}
+		sections = append(sections, section)
+		i += n
+		if i+1 >= len(s) || (s[i] != ':' && s[i] != '.') || !isDigit(s[i+1]) {
+			break
+		}
+		i++
+	}
+	return sections, i
+}
+
+// englishSection reads a number optionally followed by the side of a folio.
+func englishSection(s string) (string, int) {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	if n == 0 {
+		return "", 0
+	}
+	if n < len(s) && (s[n] == 'a' || s[n] == 'b') && boundary(s[n+1:]) {
+		n++
+	}
+	return s[:n], n
+}
+
+// hebrewMarkers are the words that may introduce a section of a Hebrew
+// address, as in "פרק א פסוק ב".
+var hebrewMarkers = []string{"פרק", "פסוק", "משנה", "הלכה", "סימן", "סעיף", "מזמור", "דף"}
+
+// hebrewAddress reads an address written with Hebrew numerals, such as
+// "א:א", "פרק ג פסוק ד", "דף ב ע״א" or "ב." from the start of s and returns
+// it along with the number of bytes it spans. Folio numbers without a side
+// span the whole folio.
+func hebrewAddress(s string) (address, int, bool) {
+	i := skipSpace(s, 0)
+	if i < len(s) && s[i] == ',' {
+		i = skipSpace(s, i+1)
+	}
+	if i == 0 {
+		return address{}, 0, false
+	}
+
+	daf := false
+	word, n := hebrewWord(s[i:])
+	if slices.Contains(hebrewMarkers, word) {
+		daf = word == "דף"
+		i = skipSpace(s, i+n)
+		word, n = hebrewWord(s[i:])
+	}
+	value, ok := hebrewNumeral(word)
+	if !ok {
+		return address{}, 0, false
+	}
+	i += n
+
+	section := strconv.Itoa(value)
+	amud, n := hebrewAmud(s[i:], daf)
+	if amud != "" {
+		section += amud
+		i += n
+	}
+	a := address{sections: []string{section}}
+
+	for {
+		j := i
+		if j < len(s) && s[j] == ':' {
+			j = skipSpace(s, j+1)
+		} else {
+			j = skipSpace(s, j)
+			marker, n := hebrewWord(s[j:])
+			if j == i || marker == "דף" || !slices.Contains(hebrewMarkers, marker) {
+				break
+			}
+			j = skipSpace(s, j+n)
+		}
+		word, n := hebrewWord(s[j:])
+		value, ok := hebrewNumeral(word)
+		if !ok {
+			break
+		}
+		a.sections = append(a.sections, strconv.Itoa(value))
+		i = j + n
+	}
+
+	if j, ok := skipDash(s, skipSpace(s, i)); ok {
+		j = skipSpace(s, j)
+		var to []string
+		for {
+			word, n := hebrewWord(s[j:])
+			value, ok := hebrewNumeral(word)
+			if !ok {
+				break
+			}
+			j += n
+			section := strconv.Itoa(value)
+			if len(to) == 0 && amud != "" {
+				side, n := hebrewAmud(s[j:], true)
+				section += side
+				j += n
+			}
+			to = append(to, section)
+			if j >= len(s) || s[j] != ':' {
+				break
+			}
+			j++
+		}
+		if len(to) > 0 && len(to) <= len(a.sections) {
+			a.toSections = fillRange(a.sections, to)
+			i = j
+		}
+	}
+	if backwards(a) {
+		return address{}, 0, false
+	}
+
+	if daf && amud == "" && len(a.sections) == 1 && a.toSections == nil {
+		a.toSections = []string{section + "b"}
+		a.sections[0] = section + "a"
+	}
+
+	return a, i, true
+}
+
+// hebrewAmud reads the side of a folio, written either as "ע״א", "עמוד ב",
+// or in the abbreviated form where a period marks the first side and a colon
+// the second. The abbreviated form is only recognized within a folio address.
+func hebrewAmud(s string, daf bool) (string, int) {
+	if daf && s != "" {
+		switch s[0] {
+		case '.':
+			return "a", 1
+		case ':':
+			if _, ok := hebrewNumeral(firstHebrewWord(s[1:])); !ok {
+				return "b", 1
+			}
+		}
+	}
+
+	i := skipSpace(s, 0)
+	word, n := hebrewWord(s[i:])
+	if word == "עמוד" {
+		i = skipSpace(s, i+n)
+		word, n = hebrewWord(s[i:])
+	} else {
+		runes := []rune(word)
+		if len(runes) != 3 || runes[0] != 'ע' || !isGeresh(runes[1]) {
+			return "", 0
+		}
+		word = string(runes[2])
+	}
+
+	switch word {
+	case "א":
+		return "a", i + n
+	case "ב":
+		return "b", i + n
+	}
+	return "", 0
+}
+
+// hebrewWord returns the run of Hebrew letters and numeral marks at the start
+// of s. A trailing ASCII quote is treated as punctuation, not a gershayim.
+func hebrewWord(s string) (string, int) {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !(unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r)) && !isGeresh(r) {
+			break
+		}
+		n += size
+	}
+	for n > 0 && s[n-1] == '"' {
+		n--
+	}
+	return s[:n], n
+}
+
+func firstHebrewWord(s string) string {
+	word, _ := hebrewWord(s[skipSpace(s, 0):])
+	return word
+}
+
+// fillRange completes the end of a range, which only spells out the sections
+// that change, from its start. The end of "1:1-3" is "1:3".
+func fillRange(from, to []string) []string {
+	full := slices.Clone(from)
+	copy(full[len(from)-len(to):], to)
+	return full
+}
+
+// backwards reports whether a is a range whose end comes before its start,
+// as in "1:5-3", which is not a citation.
+func backwards(a address) bool {
+	return a.toSections != nil && slices.CompareFunc(a.toSections, a.sections, compareSection) < 0
+}
+
+// compareSection compares two sections by their number, then by the side of
+// the folio, so that "2b" comes after "2a" and "10" after "9".
+func compareSection(a, b string) int {
+	an, aside := splitSection(a)
+	bn, bside := splitSection(b)
+	return cmp.Or(cmp.Compare(an, bn), strings.Compare(aside, bside))
+}
+
+// splitSection splits a section such as "2a" into its number and the side of
+// the folio.
+func splitSection(s string) (int, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	n, _ := strconv.Atoi(s[:i])
+	return n, s[i:]
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r != ' ' && r != '\t' && r != '\u00A0' {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// skipDash returns the index after a hyphen or dash at s[i].
+func skipDash(s string, i int) (int, bool) {
+	for _, dash := range []string{"-", "–", "—", "־"} {
+		if strings.HasPrefix(s[i:], dash) {
+			return i + len(dash), true
+		}
+	}
+	return i, false
+}
+
+// boundary reports whether s does not continue the word or number before it.
+func boundary(s string) bool {
+	if s == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
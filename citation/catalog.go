@@ -0,0 +1,274 @@
+package citation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ryanfaerman/go-sefaria"
+)
+
+// Entry holds every known way of referring to a single text.
+type Entry struct {
+	// The canonical English title, used when formatting refs.
+	Title string `json:"title"`
+
+	// Alternative English titles and abbreviations, e.g. "Gen.".
+	Titles []string `json:"titles,omitempty"`
+
+	// Hebrew titles, primary title first.
+	HeTitles []string `json:"heTitles,omitempty"`
+
+	// Commentaries are also matched by combining the collective title of
+	// their author with every title of the base text, e.g. "Rashi on Gen.".
+	CollectiveTitle   string `json:"collectiveTitle,omitempty"`
+	HeCollectiveTitle string `json:"heCollectiveTitle,omitempty"`
+	BaseTitle         string `json:"baseTitle,omitempty"`
+}
+
+// Catalog is the set of titles a Detector is able to find.
+type Catalog struct {
+	Entries []Entry `json:"entries"`
+
+	index map[string]int
+}
+
+// NewCatalog creates a catalog holding the given entries.
+func NewCatalog(entries ...Entry) *Catalog {
+	c := &Catalog{}
+	for _, e := range entries {
+		c.Add(e)
+	}
+	return c
+}
+
+// Add an entry to the catalog. Adding an entry whose title is already known
+// merges the two, so that additional variants can be layered on top of the
+// table of contents.
+func (c *Catalog) Add(e Entry) {
+	if e.Title == "" {
+		return
+	}
+	if c.index == nil {
+		c.reindex()
+	}
+
+	i, ok := c.index[e.Title]
+	if !ok {
+		c.index[e.Title] = len(c.Entries)
+		c.Entries = append(c.Entries, Entry{Title: e.Title})
+		i = len(c.Entries) - 1
+	}
+
+	existing := &c.Entries[i]
+	existing.Titles = appendUnique(existing.Titles, e.Titles...)
+	existing.HeTitles = appendUnique(existing.HeTitles, e.HeTitles...)
+	if e.CollectiveTitle != "" {
+		existing.CollectiveTitle = e.CollectiveTitle
+	}
+	if e.HeCollectiveTitle != "" {
+		existing.HeCollectiveTitle = e.HeCollectiveTitle
+	}
+	if e.BaseTitle != "" {
+		existing.BaseTitle = e.BaseTitle
+	}
+}
+
+// Lookup returns the entry with the given canonical title.
+func (c *Catalog) Lookup(title string) (Entry, bool) {
+	if c.index == nil {
+		c.reindex()
+	}
+	i, ok := c.index[title]
+	if !ok {
+		return Entry{}, false
+	}
+	return c.Entries[i], true
+}
+
+func (c *Catalog) reindex() {
+	c.index = make(map[string]int, len(c.Entries))
+	for i, e := range c.Entries {
+		c.index[e.Title] = i
+	}
+}
+
+// AddIndex adds the title variants found in an index record, as returned by
+// IndexService.Get, to the catalog.
+func (c *Catalog) AddIndex(index sefaria.Index) {
+	e := Entry{}
+	e.Title, _ = index["title"].(string)
+
+	if schema, ok := index["schema"].(map[string]any); ok {
+		titles, _ := schema["titles"].([]any)
+		for _, t := range titles {
+			title, _ := t.(map[string]any)
+			text, _ := title["text"].(string)
+			if text == "" {
+				continue
+			}
+			switch title["lang"] {
+			case "en":
+				if primary, _ := title["primary"].(bool); primary && e.Title == "" {
+					e.Title = text
+				}
+				e.Titles = append(e.Titles, text)
+			case "he":
+				e.HeTitles = append(e.HeTitles, text)
+			}
+		}
+	}
+
+	for _, key := range []string{"titleVariants", "heTitleVariants"} {
+		variants, _ := index[key].([]any)
+		for _, v := range variants {
+			if s, ok := v.(string); ok {
+				if key == "titleVariants" {
+					e.Titles = append(e.Titles, s)
+				} else {
+					e.HeTitles = append(e.HeTitles, s)
+				}
+			}
+		}
+	}
+
+	c.Add(e)
+}
+
+// FromTOC builds a catalog from Sefaria's table of contents. The table of
+// contents only holds the primary English and Hebrew title of each text, so
+// common English abbreviations of the books of Tanakh are added as well.
+func FromTOC(toc []sefaria.TOCNode) *Catalog {
+	c := NewCatalog()
+	for _, node := range toc {
+		for text := range node.Texts() {
+			e := Entry{
+				Title:             text.Title,
+				CollectiveTitle:   text.CollectiveTitle,
+				HeCollectiveTitle: string(text.HeCollectiveTitle),
+				Titles:            abbreviations[text.Title],
+			}
+			if text.HeTitle != "" {
+				e.HeTitles = []string{string(text.HeTitle)}
+			}
+			if len(text.BaseTextTitles) == 1 {
+				e.BaseTitle = text.BaseTextTitles[0]
+			}
+			c.Add(e)
+		}
+	}
+	return c
+}
+
+// FetchCatalog builds a catalog from the table of contents served by the
+// client.
+func FetchCatalog(ctx context.Context, client *sefaria.Client) (*Catalog, error) {
+	toc, err := client.Index.TableOfContents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FromTOC(toc), nil
+}
+
+// LoadCatalog reads the catalog cached at path. If nothing is cached yet the
+// catalog is fetched with the client and written to path for next time.
+func LoadCatalog(ctx context.Context, client *sefaria.Client, path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err == nil {
+		defer f.Close()
+		return ReadCatalog(f)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	c, err := FetchCatalog(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+	if _, err := c.WriteTo(out); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReadCatalog decodes a catalog previously written with WriteTo.
+func ReadCatalog(r io.Reader) (*Catalog, error) {
+	c := new(Catalog)
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+	c.reindex()
+	return c, nil
+}
+
+// WriteTo encodes the catalog as JSON.
+func (c *Catalog) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if v != "" && !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// abbreviations are the customary English abbreviations of the books of
+// Tanakh, keyed by their canonical Sefaria title.
+var abbreviations = map[string][]string{
+	"Genesis":       {"Gen.", "Gen", "Bereshit", "Bereishit"},
+	"Exodus":        {"Ex.", "Exod.", "Exod", "Shemot"},
+	"Leviticus":     {"Lev.", "Lev", "Vayikra"},
+	"Numbers":       {"Num.", "Num", "Bamidbar"},
+	"Deuteronomy":   {"Deut.", "Deut", "Devarim"},
+	"Joshua":        {"Josh.", "Josh"},
+	"Judges":        {"Judg.", "Judg"},
+	"I Samuel":      {"1 Sam.", "1 Samuel", "1 Sam"},
+	"II Samuel":     {"2 Sam.", "2 Samuel", "2 Sam"},
+	"I Kings":       {"1 Kgs.", "1 Kings", "1 Kgs"},
+	"II Kings":      {"2 Kgs.", "2 Kings", "2 Kgs"},
+	"Isaiah":        {"Isa.", "Isa"},
+	"Jeremiah":      {"Jer.", "Jer"},
+	"Ezekiel":       {"Ezek.", "Ezek"},
+	"Hosea":         {"Hos.", "Hos"},
+	"Obadiah":       {"Obad.", "Obad"},
+	"Micah":         {"Mic.", "Mic"},
+	"Habakkuk":      {"Hab.", "Hab"},
+	"Zephaniah":     {"Zeph.", "Zeph"},
+	"Haggai":        {"Hag.", "Hag"},
+	"Zechariah":     {"Zech.", "Zech"},
+	"Malachi":       {"Mal.", "Mal"},
+	"Psalms":        {"Ps.", "Psalm", "Ps", "Tehillim"},
+	"Proverbs":      {"Prov.", "Prov", "Mishlei"},
+	"Job":           {"Iyov"},
+	"Song of Songs": {"Song", "Song of Solomon", "Shir HaShirim"},
+	"Lamentations":  {"Lam.", "Lam", "Eichah"},
+	"Ecclesiastes":  {"Eccl.", "Eccl", "Kohelet"},
+	"Esther":        {"Esth.", "Esth"},
+	"Daniel":        {"Dan.", "Dan"},
+	"Nehemiah":      {"Neh.", "Neh"},
+	"I Chronicles":  {"1 Chr.", "1 Chronicles", "1 Chr"},
+	"II Chronicles": {"2 Chr.", "2 Chronicles", "2 Chr"},
+}
@@ -0,0 +1,50 @@
+package citation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_RoundTrip(t *testing.T) {
+	catalog := testCatalog()
+
+	var buf bytes.Buffer
+	_, err := catalog.WriteTo(&buf)
+	require.NoError(t, err)
+
+	loaded, err := ReadCatalog(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, catalog.Entries, loaded.Entries)
+
+	entry, ok := loaded.Lookup("Rashi on Genesis")
+	require.True(t, ok)
+	assert.Equal(t, "Genesis", entry.BaseTitle)
+	assert.Equal(t, "Rashi", entry.CollectiveTitle)
+}
+
+func TestCatalog_AddIndex(t *testing.T) {
+	catalog := testCatalog()
+	catalog.AddIndex(sefaria.Index{
+		"title": "Exodus",
+		"schema": map[string]any{
+			"titles": []any{
+				map[string]any{"lang": "en", "text": "Exodus", "primary": true},
+				map[string]any{"lang": "en", "text": "Sefer Shemot"},
+				map[string]any{"lang": "he", "text": "ספר שמות"},
+			},
+		},
+	})
+
+	entry, ok := catalog.Lookup("Exodus")
+	require.True(t, ok)
+	assert.Contains(t, entry.Titles, "Sefer Shemot")
+	assert.Equal(t, []string{"שמות", "ספר שמות"}, entry.HeTitles)
+
+	found := NewDetector(catalog).Find("Sefer Shemot 3:14")
+	require.Len(t, found, 1)
+	assert.Equal(t, "Exodus 3:14", found[0].Ref)
+}
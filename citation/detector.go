@@ -0,0 +1,242 @@
+package citation
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ryanfaerman/go-sefaria"
+)
+
+// Detector finds citations in text using the titles of a Catalog. A Detector
+// is safe for concurrent use.
+type Detector struct {
+	matcher  *matcher
+	patterns []pattern
+
+	bare bool
+}
+
+// pattern is a single title variant and the texts it may refer to.
+type pattern struct {
+	titles   []string
+	language string
+
+	// joined is set for variants ending in a period, such as "Gen.", which
+	// may be followed by an address without a space.
+	joined bool
+}
+
+type DetectorOption func(*Detector)
+
+// WithBareTitles also reports titles that are not followed by an address,
+// such as "Genesis" on its own. By default a title is only reported when it
+// is followed by a section or segment, since many titles are also ordinary
+// words.
+func WithBareTitles() DetectorOption {
+	return func(d *Detector) {
+		d.bare = true
+	}
+}
+
+// NewDetector compiles every title variant in the catalog into a Detector.
+func NewDetector(c *Catalog, opts ...DetectorOption) *Detector {
+	d := &Detector{matcher: newMatcher()}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	for _, e := range c.Entries {
+		d.add(e.Title, e.Title)
+		for _, variant := range e.Titles {
+			d.add(variant, e.Title)
+		}
+		for _, variant := range e.HeTitles {
+			d.add(variant, e.Title)
+		}
+
+		if e.BaseTitle == "" {
+			continue
+		}
+		base, ok := c.Lookup(e.BaseTitle)
+		if !ok {
+			continue
+		}
+		if e.CollectiveTitle != "" {
+			d.add(e.CollectiveTitle+" on "+base.Title, e.Title)
+			for _, variant := range base.Titles {
+				d.add(e.CollectiveTitle+" on "+variant, e.Title)
+			}
+		}
+		if e.HeCollectiveTitle != "" {
+			for _, variant := range base.HeTitles {
+				d.add(e.HeCollectiveTitle+" על "+variant, e.Title)
+			}
+		}
+	}
+
+	d.matcher.build()
+	return d
+}
+
+func (d *Detector) add(variant, title string) {
+	variant = strings.TrimSpace(variant)
+	if variant == "" {
+		return
+	}
+
+	id := d.matcher.add(foldString(variant), len(d.patterns))
+	if id == len(d.patterns) {
+		language := "en"
+		if isHebrew(variant) {
+			language = "he"
+		}
+		d.patterns = append(d.patterns, pattern{
+			language: language,
+			joined:   strings.HasSuffix(variant, "."),
+		})
+	}
+	if !slices.Contains(d.patterns[id].titles, title) {
+		d.patterns[id].titles = append(d.patterns[id].titles, title)
+	}
+}
+
+type candidate struct {
+	start, end int
+	pattern    int
+	address    address
+}
+
+// Find returns every citation in text, in the order they appear. Where
+// titles overlap the longest one wins, so "Rashi on Genesis 1:1" is reported
+// once rather than also as "Genesis 1:1".
+func (d *Detector) Find(text string) []sefaria.FoundRef {
+	runes := []rune(text)
+	offsets := make([]int, 0, len(runes)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = fold(r)
+	}
+
+	var candidates []candidate
+	for _, m := range d.matcher.search(folded) {
+		p := d.patterns[m.pattern]
+		if !boundaryBefore(runes, m.start, p.language) {
+			continue
+		}
+		if !p.joined && m.end < len(runes) && (unicode.IsLetter(runes[m.end]) || unicode.IsDigit(runes[m.end])) {
+			continue
+		}
+
+		end := offsets[m.end]
+		var (
+			a  address
+			n  int
+			ok bool
+		)
+		if p.language == "he" {
+			a, n, ok = hebrewAddress(text[end:])
+		} else {
+			a, n, ok = englishAddress(text[end:], p.joined)
+		}
+		if !ok && !d.bare {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			start:   offsets[m.start],
+			end:     end + n,
+			pattern: m.pattern,
+			address: a,
+		})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		return b.end - a.end
+	})
+
+	var out []sefaria.FoundRef
+	last := 0
+	for _, c := range candidates {
+		if c.start < last {
+			continue
+		}
+		last = c.end
+		out = append(out, d.found(text, c))
+	}
+	return out
+}
+
+func (d *Detector) found(text string, c candidate) sefaria.FoundRef {
+	p := d.patterns[c.pattern]
+
+	refs := make([]sefaria.Ref, len(p.titles))
+	candidates := make([]string, len(p.titles))
+	for i, title := range p.titles {
+		refs[i] = sefaria.Ref{
+			Book:       title,
+			Sections:   c.address.sections,
+			ToSections: c.address.toSections,
+		}
+		candidates[i] = refs[i].String()
+	}
+
+	found := sefaria.FoundRef{
+		Start:      c.start,
+		End:        c.end,
+		Text:       text[c.start:c.end],
+		Candidates: candidates,
+		Ambiguous:  len(refs) > 1,
+		Language:   p.language,
+	}
+	if !found.Ambiguous {
+		found.Ref = candidates[0]
+		found.Parsed = &refs[0]
+	}
+	return found
+}
+
+// hebrewPrefixes are the letters that attach to the front of a Hebrew word,
+// as in "ובברכות" ("and in Berakhot").
+const hebrewPrefixes = "ובכלמהש"
+
+// boundaryBefore reports whether a title starting at runes[start] begins a
+// word. Hebrew titles may be preceded by up to two prefix letters.
+func boundaryBefore(runes []rune, start int, language string) bool {
+	if start == 0 || !isWordRune(runes[start-1]) {
+		return true
+	}
+	if language != "he" {
+		return false
+	}
+	for i := start - 1; i >= 0 && i >= start-2; i-- {
+		if !strings.ContainsRune(hebrewPrefixes, runes[i]) {
+			return false
+		}
+		if i == 0 || !isWordRune(runes[i-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isHebrew(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
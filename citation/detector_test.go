@@ -0,0 +1,232 @@
+package citation
+
+import (
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCatalog() *Catalog {
+	return FromTOC([]sefaria.TOCNode{
+		{
+			Category: "Tanakh",
+			Contents: []sefaria.TOCNode{
+				{Title: "Genesis", HeTitle: "בראשית"},
+				{Title: "Exodus", HeTitle: "שמות"},
+				{Title: "Genesis Rabbah", HeTitle: "בראשית רבה"},
+			},
+		},
+		{
+			Category: "Commentary",
+			Contents: []sefaria.TOCNode{
+				{
+					Title:             "Rashi on Genesis",
+					HeTitle:           "רש״י על בראשית",
+					CollectiveTitle:   "Rashi",
+					HeCollectiveTitle: "רש״י",
+					BaseTextTitles:    []string{"Genesis"},
+				},
+			},
+		},
+		{
+			Category: "Talmud",
+			Contents: []sefaria.TOCNode{
+				{Title: "Berakhot", HeTitle: "ברכות"},
+			},
+		},
+	})
+}
+
+func TestDetector_Find(t *testing.T) {
+	detector := NewDetector(testCatalog())
+
+	tests := []struct {
+		name     string
+		input    string
+		text     string
+		ref      string
+		language string
+	}{
+		{
+			name:     "verse",
+			input:    "It says in Genesis 1:1 that",
+			text:     "Genesis 1:1",
+			ref:      "Genesis 1:1",
+			language: "en",
+		},
+		{
+			name:     "abbreviation",
+			input:    "compare Gen. 12:3.",
+			text:     "Gen. 12:3",
+			ref:      "Genesis 12:3",
+			language: "en",
+		},
+		{
+			name:     "commentary with abbreviation",
+			input:    "see Rashi on Gen. 1:1",
+			text:     "Rashi on Gen. 1:1",
+			ref:      "Rashi on Genesis 1:1",
+			language: "en",
+		},
+		{
+			name:     "longest title wins",
+			input:    "Genesis Rabbah 1:1",
+			text:     "Genesis Rabbah 1:1",
+			ref:      "Genesis Rabbah 1:1",
+			language: "en",
+		},
+		{
+			name:     "range within a chapter",
+			input:    "read Exodus 20:1-14 aloud",
+			text:     "Exodus 20:1-14",
+			ref:      "Exodus 20:1-14",
+			language: "en",
+		},
+		{
+			name:     "range ending in a longer number",
+			input:    "Genesis 1:9-10",
+			text:     "Genesis 1:9-10",
+			ref:      "Genesis 1:9-10",
+			language: "en",
+		},
+		{
+			name:     "range across chapters",
+			input:    "(Genesis 1:1 – 2:3)",
+			text:     "Genesis 1:1 – 2:3",
+			ref:      "Genesis 1:1-2:3",
+			language: "en",
+		},
+		{
+			name:     "case insensitive",
+			input:    "genesis 3",
+			text:     "genesis 3",
+			ref:      "Genesis 3",
+			language: "en",
+		},
+		{
+			name:     "talmud folio",
+			input:    "as in Berakhot 2a:5,",
+			text:     "Berakhot 2a:5",
+			ref:      "Berakhot 2a:5",
+			language: "en",
+		},
+		{
+			name:     "hebrew folio",
+			input:    "ברכות דף ב ע״א",
+			text:     "ברכות דף ב ע״א",
+			ref:      "Berakhot 2a",
+			language: "he",
+		},
+		{
+			name:     "hebrew folio with ascii quote",
+			input:    `עיין ברכות ה ע"ב`,
+			text:     `ברכות ה ע"ב`,
+			ref:      "Berakhot 5b",
+			language: "he",
+		},
+		{
+			name:     "hebrew folio abbreviated",
+			input:    "ברכות דף ה: ועוד",
+			text:     "ברכות דף ה:",
+			ref:      "Berakhot 5b",
+			language: "he",
+		},
+		{
+			name:     "hebrew whole folio",
+			input:    "ברכות דף ה ועוד",
+			text:     "ברכות דף ה",
+			ref:      "Berakhot 5a-5b",
+			language: "he",
+		},
+		{
+			name:     "hebrew chapter and verse",
+			input:    "כמו שכתוב בבראשית א:א",
+			text:     "בראשית א:א",
+			ref:      "Genesis 1:1",
+			language: "he",
+		},
+		{
+			name:     "hebrew chapter and verse with markers",
+			input:    "שמות פרק ט״ו פסוק ג",
+			text:     "שמות פרק ט״ו פסוק ג",
+			ref:      "Exodus 15:3",
+			language: "he",
+		},
+		{
+			name:     "hebrew commentary",
+			input:    "רש״י על בראשית א:א",
+			text:     "רש״י על בראשית א:א",
+			ref:      "Rashi on Genesis 1:1",
+			language: "he",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := detector.Find(tt.input)
+			require.Len(t, found, 1)
+			assert.Equal(t, tt.text, found[0].Text)
+			assert.Equal(t, tt.text, tt.input[found[0].Start:found[0].End])
+			assert.Equal(t, tt.ref, found[0].Ref)
+			assert.Equal(t, tt.language, found[0].Language)
+			require.NotNil(t, found[0].Parsed)
+			assert.Equal(t, tt.ref, found[0].Parsed.String())
+		})
+	}
+}
+
+func TestDetector_FindNothing(t *testing.T) {
+	detector := NewDetector(testCatalog())
+
+	tests := map[string]string{
+		"bare title":        "the book of Genesis is first",
+		"inside a word":     "Regenesis 1:1",
+		"ordinary number":   "Genesis 12th edition",
+		"not a numeral":     "בראשית ברא אלהים",
+		"hebrew bare title": "ספר שמות",
+		"backwards range":   "Genesis 1:1-0",
+		"backwards chapter": "Exodus 20:1-19:3 aloud",
+		"backwards folio":   "Berakhot 3a-2b",
+		"hebrew backwards":  "בראשית ב:א-א:ה",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Empty(t, detector.Find(input))
+		})
+	}
+}
+
+func TestDetector_BareTitles(t *testing.T) {
+	detector := NewDetector(testCatalog(), WithBareTitles())
+
+	found := detector.Find("the book of Genesis is first")
+	require.Len(t, found, 1)
+	assert.Equal(t, "Genesis", found[0].Ref)
+}
+
+func TestDetector_Multiple(t *testing.T) {
+	detector := NewDetector(testCatalog())
+
+	input := "Genesis 1:1, Exodus 2:3 and ברכות ב."
+	found := detector.Find(input)
+	require.Len(t, found, 3)
+	assert.Equal(t, "Genesis 1:1", found[0].Ref)
+	assert.Equal(t, "Exodus 2:3", found[1].Ref)
+	assert.Equal(t, "Berakhot 2", found[2].Ref)
+}
+
+func TestDetector_Ambiguous(t *testing.T) {
+	catalog := NewCatalog(
+		Entry{Title: "Shemot Rabbah", Titles: []string{"Sh. R."}},
+		Entry{Title: "Shir HaShirim Rabbah", Titles: []string{"Sh. R."}},
+	)
+	found := NewDetector(catalog).Find("Sh. R. 1:1")
+	require.Len(t, found, 1)
+	assert.True(t, found[0].Ambiguous)
+	assert.Empty(t, found[0].Ref)
+	assert.Nil(t, found[0].Parsed)
+	assert.Equal(t, []string{"Shemot Rabbah 1:1", "Shir HaShirim Rabbah 1:1"}, found[0].Candidates)
+}
@@ -0,0 +1,32 @@
+// Package citation detects citations of Jewish texts in free text without
+// calling the Sefaria API.
+//
+// Detection is driven by a Catalog of titles. Every English and Hebrew title
+// variant in the catalog is compiled into a single Aho–Corasick automaton, so
+// a piece of text is scanned once no matter how many titles are known. Each
+// title that is found is then followed by an attempt to read an address, such
+// as a chapter and verse ("1:1"), a Talmud folio ("2a", "דף ב ע״א") or a
+// range ("1:1-2:3").
+//
+// Commentaries are matched by combining the collective title of the author
+// with the title of the text being commented on, so "Rashi on Gen. 1:1" and
+// "רש״י על בראשית א:א" are both found given the TOC entry for Rashi on
+// Genesis.
+//
+// Building a catalog requires one request for Sefaria's table of contents.
+// Catalogs can be written to and read from disk, after which detection needs
+// no network access at all:
+//
+//	catalog, err := citation.LoadCatalog(ctx, client, "titles.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	detector := citation.NewDetector(catalog)
+//	for _, ref := range detector.Find("see Rashi on Gen. 1:1") {
+//		fmt.Println(ref.Text, "→", ref.Ref)
+//	}
+//
+// Results are reported as sefaria.FoundRef values, the same type returned by
+// the remote linker, with byte offsets into the searched text.
+package citation
@@ -0,0 +1,60 @@
+package citation
+
+import "strings"
+
+// letterValues maps each Hebrew letter, including final forms, to its value
+// as a numeral.
+var letterValues = map[rune]int{
+	'א': 1, 'ב': 2, 'ג': 3, 'ד': 4, 'ה': 5, 'ו': 6, 'ז': 7, 'ח': 8, 'ט': 9,
+	'י': 10, 'כ': 20, 'ך': 20, 'ל': 30, 'מ': 40, 'ם': 40, 'נ': 50, 'ן': 50,
+	'ס': 60, 'ע': 70, 'פ': 80, 'ף': 80, 'צ': 90, 'ץ': 90,
+	'ק': 100, 'ר': 200, 'ש': 300, 'ת': 400,
+}
+
+// isGeresh reports whether r marks a Hebrew numeral, either with the proper
+// geresh and gershayim or with the ASCII quotes commonly typed in their place.
+func isGeresh(r rune) bool {
+	return r == '׳' || r == '״' || r == '\'' || r == '"'
+}
+
+// hebrewNumeral returns the value of a Hebrew numeral such as "א", "ט״ו" or
+// "קמ״ה". Letters must appear in descending order of value with at most one
+// letter each for the tens and units, as in standard notation, which keeps
+// ordinary words from being mistaken for numbers. The customary spellings of
+// 15 and 16 as ט״ו and ט״ז are the one exception.
+func hebrewNumeral(s string) (int, bool) {
+	s = strings.TrimFunc(s, isGeresh)
+	if s == "" {
+		return 0, false
+	}
+
+	total, last, letters := 0, 1000, 0
+	var tens, units bool
+	for _, r := range s {
+		if isGeresh(r) {
+			continue
+		}
+		v, ok := letterValues[r]
+		if !ok || v > last {
+			return 0, false
+		}
+		switch {
+		case v < 10:
+			if units && !(last == 9 && !tens && (v == 6 || v == 7)) {
+				return 0, false
+			}
+			units = true
+		case v < 100:
+			if tens {
+				return 0, false
+			}
+			tens = true
+		case v < 400 && v == last:
+			return 0, false
+		}
+		total += v
+		last = v
+		letters++
+	}
+	return total, letters > 0
+}
@@ -0,0 +1,37 @@
+package citation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHebrewNumeral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		ok       bool
+	}{
+		{"א", 1, true},
+		{"י״ח", 18, true},
+		{"ט״ו", 15, true},
+		{"ט״ז", 16, true},
+		{"ל׳", 30, true},
+		{"קמ״ה", 145, true},
+		{"תשע״ה", 775, true},
+		{`ל"ה`, 35, true},
+		{"", 0, false},
+		{"ברא", 0, false},
+		{"יי", 0, false},
+		{"אב", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			value, ok := hebrewNumeral(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
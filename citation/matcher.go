@@ -0,0 +1,148 @@
+package citation
+
+import "unicode"
+
+// matcher is an Aho–Corasick automaton over runes. It finds every occurrence
+// of every pattern in a single pass over the text.
+type matcher struct {
+	nodes []node
+}
+
+type node struct {
+	next map[rune]int
+
+	// fail is the node for the longest proper suffix of this node that is
+	// also a prefix of some pattern.
+	fail int
+
+	// output is the pattern ending at this node, or -1. dict is the nearest
+	// node along the fail chain with an output, or -1.
+	output int
+	dict   int
+
+	depth int
+}
+
+// match is an occurrence of a pattern. Start and end are rune indexes.
+type match struct {
+	start, end int
+	pattern    int
+}
+
+func newMatcher() *matcher {
+	return &matcher{nodes: []node{{output: -1, dict: -1}}}
+}
+
+// add inserts a pattern, which must already be folded. It returns the id of
+// the pattern, which is shared by patterns that fold to the same runes.
+func (m *matcher) add(pattern []rune, id int) int {
+	cur := 0
+	for _, r := range pattern {
+		next, ok := m.nodes[cur].next[r]
+		if !ok {
+			next = len(m.nodes)
+			m.nodes = append(m.nodes, node{output: -1, dict: -1, depth: m.nodes[cur].depth + 1})
+			if m.nodes[cur].next == nil {
+				m.nodes[cur].next = make(map[rune]int)
+			}
+			m.nodes[cur].next[r] = next
+		}
+		cur = next
+	}
+	if m.nodes[cur].output < 0 {
+		m.nodes[cur].output = id
+	}
+	return m.nodes[cur].output
+}
+
+// build computes the fail and dictionary links. It must be called after the
+// last pattern is added and before searching.
+func (m *matcher) build() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		m.nodes[child].fail = 0
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[cur].next {
+			queue = append(queue, child)
+
+			fail := m.nodes[cur].fail
+			for {
+				if next, ok := m.nodes[fail].next[r]; ok && next != child {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					m.nodes[child].fail = 0
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+
+			f := m.nodes[child].fail
+			if m.nodes[f].output >= 0 {
+				m.nodes[child].dict = f
+			} else {
+				m.nodes[child].dict = m.nodes[f].dict
+			}
+		}
+	}
+}
+
+// search returns every match of every pattern in text, which must already be
+// folded.
+func (m *matcher) search(text []rune) []match {
+	var matches []match
+	cur := 0
+	for i, r := range text {
+		for {
+			if next, ok := m.nodes[cur].next[r]; ok {
+				cur = next
+				break
+			}
+			if cur == 0 {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+
+		for n := cur; n > 0; n = m.nodes[n].dict {
+			if m.nodes[n].output >= 0 {
+				matches = append(matches, match{
+					start:   i + 1 - m.nodes[n].depth,
+					end:     i + 1,
+					pattern: m.nodes[n].output,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// fold maps a rune to the form used for matching. Folding never changes the
+// number of runes, so indexes into folded text are also indexes into the
+// original.
+func fold(r rune) rune {
+	switch r {
+	case '״', '“', '”':
+		return '"'
+	case '׳', '‘', '’':
+		return '\''
+	case '\u00A0':
+		return ' '
+	}
+	return unicode.ToLower(r)
+}
+
+func foldString(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = fold(r)
+	}
+	return runes
+}
@@ -34,13 +34,16 @@ type Client struct {
 
 	common service
 
-	Text     *TextService
-	Index    *IndexService
-	Related  *RelatedService
-	Calendar *CalendarService
-	Lexicon  *LexiconService
-	Topics   *TopicService
-	Terms    *TermService
+	Text        *TextService
+	Index       *IndexService
+	Related     *RelatedService
+	Calendar    *CalendarService
+	Lexicon     *LexiconService
+	Topics      *TopicService
+	Terms       *TermService
+	Sheets      *SheetService
+	Collections *CollectionService
+	Linker      *LinkerService
 }
 
 type ClientOption func(*Client)
@@ -76,6 +79,9 @@ func NewClient(opts ...ClientOption) *Client {
 	c.Lexicon = (*LexiconService)(&c.common)
 	c.Topics = (*TopicService)(&c.common)
 	c.Terms = (*TermService)(&c.common)
+	c.Sheets = (*SheetService)(&c.common)
+	c.Collections = (*CollectionService)(&c.common)
+	c.Linker = (*LinkerService)(&c.common)
 
 	for _, opt := range opts {
 		opt(c)
@@ -44,10 +44,7 @@ Examples:
 		Date      string `flag:"date" desc:"the date to get the calendar for (default: today)"`
 		TimeZone  string `flag:"timezone" desc:"the timezone to use (default: auto-detected)"`
 		Tradition string `flag:"tradition" desc:"the Jewish tradition to use (ashkenaz, sefard, or mizrahi)"`
-	}{
-		TimeZone: tz.Detect(),
-		Diaspora: tz.Detect() != "" && tz.Detect() != "Asia/Jerusalem",
-	}
+	}{}
 
 	cmdCalendarGet = &cobra.Command{
 		Use:   "get",
@@ -62,7 +59,8 @@ This command retrieves the complete learning schedule for a given date, includin
 • Reading traditions and customs
 
 The command automatically detects your timezone and location to provide accurate
-calendar information. You can override these settings with command-line flags.
+calendar information. You can override these settings with command-line flags,
+or with the timezone, diaspora and tradition settings of "sefaria config".
 
 Arguments:
   None (uses today's date by default)
@@ -94,6 +92,15 @@ Examples:
   sefaria calendar get --date="2024-03-15" --tradition=sefard
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// The timezone is only detected when neither a flag nor a setting
+			// gives it, and diaspora mode follows from it.
+			if optsCalendarGet.TimeZone == "" {
+				optsCalendarGet.TimeZone = tz.Detect()
+			}
+			if !cmd.Flags().Changed("diaspora") && setting("diaspora") == "" {
+				optsCalendarGet.Diaspora = optsCalendarGet.TimeZone != "" && optsCalendarGet.TimeZone != "Asia/Jerusalem"
+			}
+
 			opts := &sefaria.CalendarGetOptions{
 				Diaspora: types.BoolInt(optsCalendarGet.Diaspora),
 				TimeZone: optsCalendarGet.TimeZone,
@@ -155,8 +162,9 @@ Notes:
   • The search is case-insensitive
   • Hebrew names are also supported
 `,
-		Args:       cobra.ExactArgs(1),
-		SuggestFor: []string{"next"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeParsha,
+		SuggestFor:        []string{"next"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			matches := phonetic.Matches(sefaria.Parshiot, args[0])
 			if len(matches) == 0 {
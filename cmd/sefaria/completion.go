@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds how long a shell waits on Sefaria for completions.
+const completionTimeout = 3 * time.Second
+
+// completionTTL is how long completions are cached for.
+const completionTTL = 24 * time.Hour
+
+// completions keeps the names Sefaria suggests for what has been typed so
+// far. Each completion runs in a process of its own, so they are kept on disk.
+var completions = cache.Default("completions", completionTTL)
+
+var (
+	cmdCompletion = &cobra.Command{
+		Use:   "completion",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a script that completes sefaria commands, flags and arguments in your shell.
+
+Besides commands and flags, the script completes:
+• Refs and titles, such as "Genesis 1:1", as suggested by Sefaria
+• Parshiot for "calendar next-read"
+• Topic slugs for "topics get" and "topics graph"
+
+Suggestions for refs and topics come from Sefaria, so completing them needs a
+network connection. They are cached for a day in your user cache directory.
+
+Run "sefaria completion <shell> --help" for how to install the script for your
+shell.
+
+Examples:
+  sefaria completion bash > ~/.local/share/bash-completion/completions/sefaria
+  sefaria completion zsh > "${fpath[1]}/_sefaria"
+  sefaria completion fish > ~/.config/fish/completions/sefaria.fish
+  sefaria completion powershell >> $PROFILE
+`,
+	}
+
+	cmdCompletionBash = &cobra.Command{
+		Use:   "bash",
+		Short: "Generate the completion script for bash",
+		Long: `Generate the completion script for bash.
+
+The script needs the bash-completion package. If it is not installed yet, install
+it with your package manager, such as "apt install bash-completion" or
+"brew install bash-completion@2".
+
+To load completions in your current shell:
+
+  source <(sefaria completion bash)
+
+To load completions for every new shell, run once:
+
+  Linux:
+    sefaria completion bash > ~/.local/share/bash-completion/completions/sefaria
+
+  macOS:
+    sefaria completion bash > $(brew --prefix)/etc/bash_completion.d/sefaria
+
+You will need to start a new shell for this setup to take effect.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return root.GenBashCompletionV2(cmd.OutOrStdout(), true)
+		},
+	}
+
+	cmdCompletionZsh = &cobra.Command{
+		Use:   "zsh",
+		Short: "Generate the completion script for zsh",
+		Long: `Generate the completion script for zsh.
+
+If shell completion is not already enabled in your environment, enable it by
+running once:
+
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+To load completions in your current shell:
+
+  source <(sefaria completion zsh)
+
+To load completions for every new shell, run once:
+
+  Linux:
+    sefaria completion zsh > "${fpath[1]}/_sefaria"
+
+  macOS:
+    sefaria completion zsh > $(brew --prefix)/share/zsh/site-functions/_sefaria
+
+You will need to start a new shell for this setup to take effect.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return root.GenZshCompletion(cmd.OutOrStdout())
+		},
+	}
+
+	cmdCompletionFish = &cobra.Command{
+		Use:   "fish",
+		Short: "Generate the completion script for fish",
+		Long: `Generate the completion script for fish.
+
+To load completions in your current shell:
+
+  sefaria completion fish | source
+
+To load completions for every new shell, run once:
+
+  sefaria completion fish > ~/.config/fish/completions/sefaria.fish
+
+You will need to start a new shell for this setup to take effect.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return root.GenFishCompletion(cmd.OutOrStdout(), true)
+		},
+	}
+
+	cmdCompletionPowerShell = &cobra.Command{
+		Use:   "powershell",
+		Short: "Generate the completion script for PowerShell",
+		Long: `Generate the completion script for PowerShell.
+
+To load completions in your current shell:
+
+  sefaria completion powershell | Out-String | Invoke-Expression
+
+To load completions for every new shell, add the output of the above command
+to your PowerShell profile:
+
+  sefaria completion powershell >> $PROFILE
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return root.GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
+		},
+	}
+)
+
+// completeRef completes a ref or title given as a single argument or flag
+// value, such as each of the refs of "topics recommend".
+func completeRef(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return names(cmd, "ref", toComplete, titles), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRefArgs completes a ref that may be spread over several arguments,
+// as "text get Genesis 1:1" allows. The arguments before the one being
+// completed are taken as the start of the ref, and left out of each
+// suggestion, since the shell only replaces the word being completed.
+func completeRefArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeRef(cmd, args, toComplete)
+	}
+
+	prefix := strings.Join(args, " ") + " "
+	var out []string
+	for _, name := range names(cmd, "ref", prefix+toComplete, titles) {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			out = append(out, rest)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTopic completes the slug of a topic.
+func completeTopic(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return names(cmd, "Topic", toComplete, slugs), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeParsha completes the name of a parsha, which are known without
+// asking Sefaria.
+func completeParsha(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var out []string
+	for _, parsha := range sefaria.Parshiot {
+		if strings.HasPrefix(strings.ToLower(parsha), strings.ToLower(toComplete)) {
+			out = append(out, parsha)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// names returns what Sefaria suggests for the partial name query, limited to
+// names of the type kind, as picked out of its answer by pick. Suggestions
+// are cached, and none are given when Sefaria cannot be reached in time.
+func names(cmd *cobra.Command, kind, query string, pick func(*sefaria.TermCompletions) []string) []string {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	key := kind + "\x00" + strings.ToLower(query)
+	var out []string
+	if completions.Get(key, &out) {
+		return out
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	c := client
+	if c == nil {
+		c = sefaria.NewClient()
+	}
+	result, err := c.Terms.Name(ctx, query, &sefaria.TermNameOptions{Type: kind})
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("cannot get completions for %q: %v", query, err), true)
+		return nil
+	}
+
+	out = pick(result)
+	if err := completions.Set(key, out); err != nil {
+		cobra.CompDebugln(fmt.Sprintf("cannot cache completions: %v", err), true)
+	}
+	return out
+}
+
+func titles(result *sefaria.TermCompletions) []string {
+	return result.CompletionTitles
+}
+
+func slugs(result *sefaria.TermCompletions) []string {
+	var out []string
+	for _, c := range result.Completions {
+		if c.Key != "" {
+			out = append(out, c.Key)
+		}
+	}
+	return out
+}
+
+func init() {
+	root.CompletionOptions.DisableDefaultCmd = true
+	cmdCompletion.AddCommand(cmdCompletionBash, cmdCompletionZsh, cmdCompletionFish, cmdCompletionPowerShell)
+
+	root.AddCommand(cmdCompletion)
+}
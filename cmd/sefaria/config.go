@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/cache"
+	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// settingsPath is where the configuration file is kept, and
+	// settingsFile is what it holds.
+	settingsPath string
+	settingsFile *settings.File
+
+	// profile is the name of the profile in use, and resolved the settings
+	// in effect under it.
+	profile  string
+	resolved []settings.Value
+)
+
+var (
+	cmdConfig = &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration file and its profiles",
+		Long: `Config commands manage the settings the CLI uses when a flag is not given.
+
+Settings are kept in a YAML file at ~/.config/sefaria/config.yaml, or in
+$XDG_CONFIG_HOME/sefaria/config.yaml when XDG_CONFIG_HOME is set. Set
+SEFARIA_CONFIG to use another file.
+
+The file holds named profiles, so that you can keep settings for different
+uses side by side. The profile in use is chosen with --profile, or else
+SEFARIA_PROFILE, or else the profile setting of the file, or else "default".
+
+Each setting is taken from the first of:
+• The command line flag, such as --output-format
+• The environment variable, such as SEFARIA_OUTPUT_FORMAT
+• The profile in use
+• The built in default
+
+Settings:
+` + settingsHelp() + `
+Examples:
+  sefaria config list
+  sefaria config set output-format text
+  sefaria config set versions.english "The Koren Jerusalem Bible"
+  sefaria config set timezone Asia/Jerusalem --profile israel
+  sefaria config set profile israel
+  sefaria config get tradition
+`,
+	}
+
+	cmdConfigGet = &cobra.Command{
+		Use:   "get [key]",
+		Short: "Get the value of a setting",
+		Long: `Get the value a setting has under the profile in use, after any environment
+variables are applied.
+
+Arguments:
+  key    The setting to get, such as "output-format" or "versions.english"
+
+Examples:
+  sefaria config get output-format
+  sefaria config get timezone --profile israel
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSettingKey,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			if key == "profile" {
+				renderer.Render(profile)
+				return nil
+			}
+			if _, err := settings.Lookup(key); err != nil {
+				return err
+			}
+
+			value := setting(key)
+			if value == "" {
+				return fmt.Errorf("%s is not set in profile %s", key, profile)
+			}
+			renderer.Render(value)
+			return nil
+		},
+	}
+
+	cmdConfigSet = &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "Set a setting in a profile",
+		Long: `Set a setting in the profile in use, creating the profile and the
+configuration file if needed. An empty value removes the setting.
+
+The "profile" key sets the profile used when none is chosen with --profile or
+SEFARIA_PROFILE.
+
+Arguments:
+  key      The setting to set, such as "output-format" or "versions.english"
+  value    The value to give it
+
+Examples:
+  sefaria config set output-format text
+  sefaria config set diaspora false --profile israel
+  sefaria config set versions.hebrew "Miqra according to the Masorah"
+  sefaria config set tradition ""
+  sefaria config set profile israel
+`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeSettingKey,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			if key == "profile" {
+				settingsFile.Profile = value
+			} else {
+				k, err := settings.Lookup(key)
+				if err != nil {
+					return err
+				}
+				if err := k.Check(value); err != nil {
+					return err
+				}
+				settingsFile.Get(profile, true).Set(key, value)
+			}
+
+			if err := settingsFile.Save(settingsPath); err != nil {
+				return fmt.Errorf("cannot save config: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmdConfigList = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the settings in effect",
+		Long: `List the settings in effect under the profile in use, with where each one
+came from: the profile, or the environment variable that overrides it.
+
+Flags given on the command line override these settings, and settings that are
+not listed take their built in defaults.
+
+Examples:
+  sefaria config list
+  sefaria config list --profile israel --output-format=yaml
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			renderer.Render(resolved)
+			return nil
+		},
+	}
+)
+
+// loadSettings reads the configuration file and resolves the settings in
+// effect under the profile in use.
+func loadSettings() error {
+	var err error
+	settingsPath, err = settings.Path()
+	if err != nil {
+		return err
+	}
+	settingsFile, err = settings.Load(settingsPath)
+	if err != nil {
+		return fmt.Errorf("cannot load config: %w", err)
+	}
+
+	profile = settingsFile.Active(config.Profile)
+	resolved = settingsFile.Resolve(profile)
+	return nil
+}
+
+// applySettings gives the flags of cmd that were not set on the command line
+// the values of the settings in effect. Settings for flags cmd does not have
+// are left alone. Flags given a setting are not marked as changed, so that
+// Changed still tells what was given on the command line.
+func applySettings(cmd *cobra.Command) error {
+	var versions []string
+	for _, v := range resolved {
+		if _, err := settings.Lookup(v.Key); err != nil {
+			return fmt.Errorf("cannot apply %s: %w", v.Source, err)
+		}
+
+		switch lang, ok := strings.CutPrefix(v.Key, "versions."); {
+		case ok:
+			versions = append(versions, lang+"|"+v.Value)
+		case v.Key == "cache-dir":
+			completions = cache.New(filepath.Join(v.Value, "completions"), completionTTL)
+		default:
+			if err := setFlag(cmd, v.Key, v.Value); err != nil {
+				return fmt.Errorf("cannot apply %s from %s: %w", v.Key, v.Source, err)
+			}
+		}
+	}
+
+	// The default versions are given together, and only when no version was
+	// given on the command line.
+	if f := cmd.Flags().Lookup("version"); f != nil && !f.Changed {
+		for _, v := range versions {
+			if err := cmd.Flags().Set("version", v); err != nil {
+				return fmt.Errorf("cannot apply default version %s: %w", v, err)
+			}
+		}
+		f.Changed = false
+	}
+	return nil
+}
+
+// setFlag sets the flag called name, unless cmd has no such flag or it was
+// given on the command line.
+func setFlag(cmd *cobra.Command, name, value string) error {
+	f := cmd.Flags().Lookup(name)
+	if f == nil || f.Changed {
+		return nil
+	}
+	if err := cmd.Flags().Set(name, value); err != nil {
+		return err
+	}
+	f.Changed = false
+	return nil
+}
+
+// setting returns the value of the setting called key, or "" if it is not
+// set.
+func setting(key string) string {
+	for _, v := range resolved {
+		if v.Key == key {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+// settingsHelp lists the settings for the config command's help.
+func settingsHelp() string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, k := range settings.Keys {
+		fmt.Fprintf(tw, "  %s\t%s\n", k.Name, k.Desc)
+	}
+	fmt.Fprintf(tw, "  %s\t%s\n", "versions.<language>", "the version of a language text get uses by default")
+	tw.Flush()
+	return b.String()
+}
+
+// completeSettingKey completes the key of a setting, with its description.
+func completeSettingKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	keys := []string{"profile"}
+	for _, k := range settings.Keys {
+		keys = append(keys, fmt.Sprintf("%s\t%s", k.Name, k.Desc))
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	cmdConfig.AddCommand(cmdConfigGet, cmdConfigSet, cmdConfigList)
+
+	root.AddCommand(cmdConfig)
+}
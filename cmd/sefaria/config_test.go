@@ -0,0 +1,178 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+// settingsOpts are flags with settings of the same name, as a command
+// declares them.
+type settingsOpts struct {
+	OutputFormat string   `flag:"output-format"`
+	Tradition    string   `flag:"tradition"`
+	Diaspora     bool     `flag:"diaspora"`
+	Versions     []string `flag:"version"`
+}
+
+const settingsYAML = `profile: default
+profiles:
+  default:
+    output-format: text
+    tradition: ashkenaz
+    diaspora: "true"
+    versions:
+      english: The Koren Jerusalem Bible
+  israel:
+    output-format: csv
+    diaspora: "false"
+`
+
+// withSettings points the CLI at a configuration file holding yaml, and
+// clears the environment variables that would override it.
+func withSettings(t *testing.T, yaml string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SEFARIA_CONFIG", path)
+	for _, env := range []string{"SEFARIA_PROFILE", "SEFARIA_OUTPUT_FORMAT", "SEFARIA_TRADITION", "SEFARIA_DIASPORA", "SEFARIA_VERSIONS_ENGLISH"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+
+	profile := config.Profile
+	t.Cleanup(func() { config.Profile = profile })
+}
+
+// runSettings parses args into a command with settingsOpts, then applies the
+// settings in effect as the CLI does before running a command.
+func runSettings(t *testing.T, args ...string) (*cobra.Command, *settingsOpts) {
+	t.Helper()
+	opts := &settingsOpts{OutputFormat: "json", Tradition: "sefard"}
+	cmd := &cobra.Command{Use: "test"}
+	if err := gpflag.ParseTo(opts, cmd.Flags()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if err := applySettings(cmd); err != nil {
+		t.Fatal(err)
+	}
+	return cmd, opts
+}
+
+func TestApplySettings_Precedence(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{name: "default", yaml: "", want: "json"},
+		{name: "profile", yaml: settingsYAML, want: "text"},
+		{name: "environment", yaml: settingsYAML, env: map[string]string{"SEFARIA_OUTPUT_FORMAT": "yaml"}, want: "yaml"},
+		{name: "flag", yaml: settingsYAML, env: map[string]string{"SEFARIA_OUTPUT_FORMAT": "yaml"}, args: []string{"--output-format=xml"}, want: "xml"},
+		{name: "flag over profile", yaml: settingsYAML, args: []string{"--output-format=xml"}, want: "xml"},
+		{name: "other profile", yaml: settingsYAML, env: map[string]string{"SEFARIA_PROFILE": "israel"}, want: "csv"},
+		{name: "missing profile", yaml: settingsYAML, env: map[string]string{"SEFARIA_PROFILE": "none"}, want: "json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withSettings(t, tt.yaml)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cmd, opts := runSettings(t, tt.args...)
+			if opts.OutputFormat != tt.want {
+				t.Errorf("output-format = %q, want %q", opts.OutputFormat, tt.want)
+			}
+			if got, want := cmd.Flags().Changed("output-format"), len(tt.args) > 0; got != want {
+				t.Errorf("Changed(output-format) = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestApplySettings_NotChanged checks that flags given a setting do not look
+// as if they were given on the command line, which would keep --template from
+// choosing the template format and diaspora mode from following the timezone.
+func TestApplySettings_NotChanged(t *testing.T) {
+	withSettings(t, settingsYAML)
+
+	cmd, opts := runSettings(t, "--tradition=mizrahi")
+	if opts.OutputFormat != "text" || !opts.Diaspora || opts.Tradition != "mizrahi" {
+		t.Fatalf("settings not applied: %+v", opts)
+	}
+	for _, name := range []string{"output-format", "diaspora", "version"} {
+		if cmd.Flags().Changed(name) {
+			t.Errorf("Changed(%s) = true for a flag set from the profile", name)
+		}
+	}
+	if !cmd.Flags().Changed("tradition") {
+		t.Error("Changed(tradition) = false for a flag given on the command line")
+	}
+	if got := setting("diaspora"); got != "true" {
+		t.Errorf(`setting("diaspora") = %q, want "true"`, got)
+	}
+}
+
+func TestApplySettings_Versions(t *testing.T) {
+	withSettings(t, settingsYAML)
+
+	_, opts := runSettings(t)
+	if want := []string{"english|The Koren Jerusalem Bible"}; !slices.Equal(opts.Versions, want) {
+		t.Errorf("versions = %q, want %q", opts.Versions, want)
+	}
+
+	t.Setenv("SEFARIA_VERSIONS_HEBREW", "Tanach with Nikkud")
+	_, opts = runSettings(t)
+	want := []string{"english|The Koren Jerusalem Bible", "hebrew|Tanach with Nikkud"}
+	if !slices.Equal(opts.Versions, want) {
+		t.Errorf("versions = %q, want %q", opts.Versions, want)
+	}
+
+	_, opts = runSettings(t, "--version=english|William Davidson Edition")
+	if want := []string{"english|William Davidson Edition"}; !slices.Equal(opts.Versions, want) {
+		t.Errorf("versions = %q, want only the one on the command line: %q", opts.Versions, want)
+	}
+}
+
+func TestApplySettings_UnknownKey(t *testing.T) {
+	withSettings(t, "profiles:\n  default:\n    colour: blue\n")
+
+	cmd := &cobra.Command{Use: "test"}
+	if err := loadSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if err := applySettings(cmd); err == nil {
+		t.Error("applySettings() error = nil, want an error for an unknown setting")
+	}
+}
+
+func TestApplySettings_CacheDir(t *testing.T) {
+	dir := t.TempDir()
+	withSettings(t, "profiles:\n  default:\n    cache-dir: "+dir+"\n")
+	saved := completions
+	t.Cleanup(func() { completions = saved })
+
+	runSettings(t)
+	if err := completions.Set("Gen", []string{"Genesis"}); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "completions", "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Errorf("completions cached in %q, want one file in %s", files, filepath.Join(dir, "completions"))
+	}
+}
@@ -18,7 +18,7 @@ How It Works:
   algorithm markers to ensure proper display. The process involves two steps:
 
   1. Detection and Marking:
-   - Automatically detects Hebrew and Arabic characters
+   - Automatically detects right-to-left characters
    - Wraps RTL text sequences with Unicode Right-to-Left Mark (RLM) and 
      Left-to-Right Mark (LRM) characters
    - Preserves LTR text unchanged
@@ -28,15 +28,30 @@ How It Works:
    - Ensures RTL text appears right-to-left in most terminals and viewers
    - Handles mixed LTR/RTL content appropriately
 
+  3. Wrapping:
+   - The text output format wraps long values to the width of the terminal,
+     or to $COLUMNS when the output is not a terminal
+   - Lines are wrapped before they are reordered, so the lines of a long
+     Hebrew verse read from top to bottom, each aligned on the right
+
 Supported Character Sets:
   Hebrew (עברית)
   Arabic (العربية)
+  Syriac (ܣܘܪܝܝܐ)
+  Thaana and N'Ko
 
-  These character sets are generally used for Hebrew, Arabic, Farsi, Yiddish, and others.
+  Text is classified by its Unicode bidi class rather than its script, so
+  every right-to-left script is handled, including Yiddish, Ladino in Hebrew
+  script, Aramaic, Persian and the Arabic presentation forms.
 
 Configuration:
   --no-bidi          Disable bidirectional text processing
                      Use when piping to programs that handle Unicode bidi correctly
+  --bidi-strategy    How RTL text is marked in json, csv and markdown output:
+                       marks     RLM/LRM marks (default), laid out for the terminal
+                       isolates  FSI/PDI isolates, left for the reader to lay out
+                       html      <span dir="rtl"> elements, for web pages
+                       raw       no marking at all, for data pipelines
 
 
 Troubleshooting:
@@ -76,19 +91,82 @@ Structured Formats:
 Tabular Formats:
   csv               CSV format (for flat data only)
 
+Document Formats:
+  markdown          Markdown, for documents and messages: fields are listed
+                    under headings, and lists of records are pipe tables
+  md                Alias for markdown
+  html              An HTML fragment: fields are description lists, lists
+                    of records are tables, and Hebrew cells are marked
+                    dir="rtl"
+  html-page         A standalone HTML page, ready to open in a browser
+
 Human-Readable Formats:
   text              Human-readable text format
   pretty            Alias for text
   human             Alias for text
+  bilingual         Texts with English on the left and Hebrew on the right,
+                    aligned by segment; stacked on terminals narrower than
+                    60 columns. Other values are shown as text.
+
+  The text format shows lists of records as tables fit to the width of the
+  terminal, wrapping cells that do not fit, or cutting them short with
+  --truncate. On a terminal, tables have borders and a bold header, without
+  colors when NO_COLOR is set; in a file or a pipe, they are plain text
+  padded with spaces.
 
 Plain Text Formats:
   plain             Plain text (one item per line)
   shell             Alias for plain
 
+Template Format:
+  template          Output rendered with a Go template, given with
+                    --template or --template-file. Giving a template picks
+                    this format unless another one is asked for.
+  tmpl              Alias for template
+
+  Fields are named by their Go names, as in {{.Title.English}}. Besides the
+  builtin functions, templates may use:
+    hebrew          The Hebrew numeral for a number: {{hebrew 5785}}
+    bidi            Marks the RTL text of a value as --bidi-strategy asks
+    dir             The direction of a string: ltr, rtl or auto
+    date            Formats a date with a Go layout, or iso, rfc3339,
+                    rfc1123 or kitchen: {{.Date | date "Jan 2, 2006"}}
+    now             The current time
+    url             The sefaria.org link to read a ref: {{url .Ref}}
+    topicURL        The sefaria.org link to a topic, by its slug
+    sheetURL        The sefaria.org link to a source sheet, by its id
+    searchURL       The sefaria.org link to the results of a search
+    join            Joins a list of strings: {{join ", " .Aliyot}}
+    json            The value as JSON
+
+Selecting Fields:
+  --fields selects the fields to output, as a comma separated list of paths.
+  A path names a field by its JSON name, such as title.en, a key of a map or
+  an index of a list, such as extraDetails.aliyot.0. A field of a list's
+  items is selected from each of them. Keys holding dots are given in
+  brackets: versions["Tanakh: The Holy Scriptures"].
+
+Filtering:
+  --filter keeps only the values matching an expression; for a list, the
+  items matching it. Paths are compared with quoted strings, numbers, true,
+  false or null using ==, !=, <, <=, >, >= and =~ (a regular expression).
+  A path on its own matches when it is set. Expressions are combined with
+  && and ||, negated with ! and grouped with parentheses. A path that leads
+  to several values matches when any of them does.
+
+  Values are filtered before their fields are selected, so a filter may use
+  fields that are not output.
+
 Examples:
   sefaria text get "Genesis 1:1" --output-format=yaml
   sefaria terms completions "torah" --output-format=text
   sefaria index contents --output-format=csv
+  sefaria calendar get --output-format=markdown
+  sefaria calendar get --output-format=html-page > calendar.html
+  sefaria calendar get --fields title.en,ref
+  sefaria calendar get --template '{{range .}}{{.Title.English}}: {{.Ref}}{{"\n"}}{{end}}'
+  sefaria calendar get --filter 'category == "Daf Yomi"' --fields ref
+  sefaria calendar get --filter 'order < 5 && !extraDetails.aliyot'
 `,
 	}
 
@@ -131,7 +209,7 @@ Basic Text Retrieval:
 
 Get Text with Options:
   sefaria text get "Genesis 1:1" --output-format=yaml
-  sefaria text get "Berakhot 2a" --lang=he
+  sefaria text get "Berakhot 2a" --version hebrew
 
 Explore Available Content:
   sefaria index contents
@@ -169,7 +247,13 @@ Debugging and Logging:
 )
 
 func init() {
-	helpBidi.SetOut(bidi.NewWriter(root.OutOrStdout(), true))
+	out := bidi.NewWriter(root.OutOrStdout(), true)
+	helpBidi.SetOut(out)
+	help := helpBidi.HelpFunc()
+	helpBidi.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		help(cmd, args)
+		out.Flush()
+	})
 	root.AddCommand(
 		helpOutput,
 		helpLogging,
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+var (
+	cmdIndex = &cobra.Command{
+		Use:   "index",
+		Short: "Explore the structure of Sefaria's library",
+		Long: `Index commands describe the texts in Sefaria's library and how they are built.
+
+Every text in Sefaria has an index record that gives its titles in English and
+Hebrew, its categories, its authors and the structure of its sections, such as
+chapters and verses or dapim and lines.
+
+These commands let you:
+• Browse the table of contents of the whole library
+• Read the index record of a single text
+• See how many chapters and verses a text has
+
+Examples:
+  sefaria index contents
+  sefaria index get Genesis
+  sefaria index shape Genesis
+  sefaria index shape Rashi --dependents
+`,
+	}
+
+	cmdIndexContents = &cobra.Command{
+		Use:   "contents",
+		Short: "Get the table of contents of the library",
+		Long: `Get the table of contents of Sefaria's library.
+
+The table of contents is a tree of categories, such as Tanakh, Mishnah and
+Talmud, holding the titles of every text in them. It is large, so consider
+a structured output format for it.
+
+Examples:
+  sefaria index contents --output-format=json-pretty
+  sefaria index contents --output-format=yaml
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contents, err := client.Index.Contents(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cannot get table of contents: %w", err)
+			}
+			renderer.Render(contents)
+			return nil
+		},
+	}
+
+	cmdIndexGet = &cobra.Command{
+		Use:   "get [title]",
+		Short: "Get the index record of a text",
+		Long: `Get the index record of a text.
+
+The record holds the text's titles and their variants in English and Hebrew,
+its categories, and the schema of its sections.
+
+Arguments:
+  title    The title of the text, such as "Genesis" or "Mishnah Berakhot"
+
+Examples:
+  sefaria index get Genesis
+  sefaria index get "Mishnah Berakhot" --output-format=yaml
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			index, err := client.Index.Get(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				return fmt.Errorf("cannot get index: %w", err)
+			}
+			renderer.Render(index)
+			return nil
+		},
+	}
+
+	optsIndexShape = &struct {
+		*sefaria.IndexShapeOptions
+	}{}
+	cmdIndexShape = &cobra.Command{
+		Use:   "shape [title]",
+		Short: "Get the shape of a text",
+		Long: `Get the shape of a text: its sections and how long each one is.
+
+For a text such as Genesis, the shape lists the number of verses in each
+chapter. A title may also be a category, such as "Tanakh", to get the shape
+of every text in it.
+
+Arguments:
+  title    The title of a text or category, such as "Genesis" or "Tanakh"
+
+Options:
+  --depth         How many levels of the text's structure to return (0 = all)
+  --dependents    Include commentaries and other texts that depend on the text
+
+Examples:
+  sefaria index shape Genesis
+  sefaria index shape Tanakh --depth 1
+  sefaria index shape Genesis --dependents --output-format=text
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shapes, err := client.Index.Shape(cmd.Context(), strings.Join(args, " "), optsIndexShape.IndexShapeOptions)
+			if err != nil {
+				return fmt.Errorf("cannot get shape: %w", err)
+			}
+			renderer.Render(shapes)
+			return nil
+		},
+	}
+)
+
+func init() {
+	if err := gpflag.ParseTo(optsIndexShape, cmdIndexShape.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	cmdIndex.AddCommand(cmdIndexContents, cmdIndexGet, cmdIndexShape)
+
+	root.AddCommand(cmdIndex)
+}
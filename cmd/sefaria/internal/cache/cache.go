@@ -0,0 +1,94 @@
+// Package cache keeps the results of API calls on disk for a while, so that
+// commands run one after another, such as shell completions, need not ask
+// Sefaria the same question each time.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache stores values as JSON files in a directory, each kept for a fixed
+// time.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// New returns a cache that keeps its values in dir for ttl. The directory is
+// created when the first value is stored.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// Default returns a cache kept in the sefaria directory of the user's cache
+// directory, under name. It returns nil when there is no cache directory; a
+// nil Cache stores nothing.
+func Default(name string, ttl time.Duration) *Cache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return New(filepath.Join(dir, "sefaria", name), ttl)
+}
+
+// Get reads the value stored under key into v. It reports whether there was
+// a value that had not expired yet.
+func (c *Cache) Get(key string, v any) bool {
+	if c == nil {
+		return false
+	}
+
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Set stores v under key.
+func (c *Cache) Set(key string, v any) error {
+	if c == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that a completion running at the
+	// same time never reads half a value.
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// path returns the file a key is stored in. Keys are hashed, as they may hold
+// anything a user types.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_SetGet(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "completions"), time.Hour)
+
+	var got []string
+	if c.Get("Gen", &got) {
+		t.Fatal("Get() found a value before one was set")
+	}
+	if err := c.Set("Gen", []string{"Genesis", "Genesis Rabbah"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !c.Get("Gen", &got) {
+		t.Fatal("Get() found no value after one was set")
+	}
+	if want := []string{"Genesis", "Genesis Rabbah"}; !slices.Equal(got, want) {
+		t.Errorf("Get() = %q, want %q", got, want)
+	}
+}
+
+func TestCache_Expiry(t *testing.T) {
+	c := New(t.TempDir(), time.Hour)
+	if err := c.Set("Gen", []string{"Genesis"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		age  time.Duration
+		want bool
+	}{
+		{59 * time.Minute, true},
+		{61 * time.Minute, false},
+		{48 * time.Hour, false},
+	} {
+		modified := time.Now().Add(-tt.age)
+		if err := os.Chtimes(c.path("Gen"), modified, modified); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		if ok := c.Get("Gen", &got); ok != tt.want {
+			t.Errorf("Get() of a value stored %v ago = %v, want %v", tt.age, ok, tt.want)
+		}
+	}
+
+	// Setting the value again starts its time over.
+	if err := c.Set("Gen", []string{"Genesis"}); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if !c.Get("Gen", &got) {
+		t.Error("Get() of a value stored again found nothing")
+	}
+}
+
+func TestCache_Path(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, time.Hour)
+
+	// Keys hold whatever was typed, so they are hashed rather than used as
+	// file names.
+	keys := []string{"Gen", "gen", "../../etc/passwd", "בראשית", ""}
+	paths := make(map[string]bool)
+	for _, key := range keys {
+		path := c.path(key)
+		if filepath.Dir(path) != dir {
+			t.Errorf("path(%q) = %s, want a file in %s", key, path, dir)
+		}
+		if name := filepath.Base(path); len(name) != 64+len(".json") || !strings.HasSuffix(name, ".json") {
+			t.Errorf("path(%q) = %s, want a sha256 named .json file", key, path)
+		}
+		paths[path] = true
+	}
+	if len(paths) != len(keys) {
+		t.Errorf("%d keys share %d paths", len(keys), len(paths))
+	}
+	if c.path("Gen") != c.path("Gen") {
+		t.Error("path() differs for the same key")
+	}
+
+	if err := c.Set("../../etc/passwd", "x"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(c.path("../../etc/passwd")) {
+		t.Errorf("directory holds %v, want only the value's file and no temporary ones", entries)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		t.Skip("no user cache directory")
+	}
+	c := Default("completions", time.Hour)
+	if want := filepath.Join(dir, "sefaria", "completions"); c == nil || c.dir != want {
+		t.Errorf("Default() = %+v, want a cache in %s", c, want)
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+	if err := c.Set("Gen", []string{"Genesis"}); err != nil {
+		t.Errorf("Set() error = %v, want nil", err)
+	}
+	var got []string
+	if c.Get("Gen", &got) {
+		t.Error("Get() found a value in a nil cache")
+	}
+}
+
+func TestCache_Corrupt(t *testing.T) {
+	c := New(t.TempDir(), time.Hour)
+	if err := os.WriteFile(c.path("Gen"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if c.Get("Gen", &got) {
+		t.Error("Get() of a corrupt value reported a value")
+	}
+}
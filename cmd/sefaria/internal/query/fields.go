@@ -0,0 +1,268 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fields is a tree of the paths selected with --fields. Each node holds the
+// segments selected under it, in the order they were first given.
+type fields struct {
+	name     string
+	children []*fields
+}
+
+// parseFields parses a comma separated list of paths, such as
+// "title.en,ref".
+func parseFields(spec string) (*fields, error) {
+	root := &fields{}
+	for _, p := range splitFields(spec) {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		segs, err := parsePath(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		n := root
+		for _, seg := range segs {
+			n = n.child(seg)
+		}
+	}
+	if len(root.children) == 0 {
+		return nil, errors.New("no fields given")
+	}
+	return root, nil
+}
+
+// splitFields splits spec at the commas that are not in brackets.
+func splitFields(spec string) []string {
+	var (
+		out   []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(spec); i++ {
+		switch c := spec[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			out = append(out, spec[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, spec[start:])
+}
+
+func (n *fields) child(name string) *fields {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &fields{name: name}
+	n.children = append(n.children, c)
+	return c
+}
+
+// leaf reports whether the whole value is selected, rather than some of the
+// values in it.
+func (n *fields) leaf() bool { return len(n.children) == 0 }
+
+// index returns the index the node selects from a slice, if it selects one.
+func (n *fields) index() (int, bool) {
+	if len(n.children) != 1 {
+		return 0, false
+	}
+	i, err := strconv.Atoi(n.children[0].name)
+	return i, err == nil
+}
+
+// projectType returns the type values of type t are projected to: structs
+// holding only the selected fields, with the tags they had, slices and maps
+// of those, and the selected values themselves.
+func (n *fields) projectType(t reflect.Type) (reflect.Type, error) {
+	if n.leaf() {
+		return t, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := n.projectType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+
+	case reflect.Interface:
+		// What an interface holds is only known from its value.
+		return t, nil
+
+	case reflect.Slice, reflect.Array:
+		if _, ok := n.index(); ok {
+			return n.children[0].projectType(t.Elem())
+		}
+		elem, err := n.projectType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+
+	case reflect.Map:
+		var elem reflect.Type
+		for _, c := range n.children {
+			ct, err := c.projectType(t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			if elem != nil && elem != ct {
+				// Keys selected differently lead to values of different
+				// types, which only an interface can hold.
+				return reflect.MapOf(t.Key(), reflect.TypeFor[any]()), nil
+			}
+			elem = ct
+		}
+		return reflect.MapOf(t.Key(), elem), nil
+
+	case reflect.Struct:
+		var out []reflect.StructField
+		for _, c := range n.children {
+			f, ok := field(t, c.name)
+			if !ok {
+				return nil, fmt.Errorf("%w %q in %s", ErrUnknownField, c.name, t)
+			}
+			for _, o := range out {
+				if o.Name == f.Name {
+					return nil, fmt.Errorf("field %q of %s is selected twice", c.name, t)
+				}
+			}
+			ft, err := c.projectType(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, reflect.StructField{Name: f.Name, Type: ft, Tag: f.Tag})
+		}
+		return reflect.StructOf(out), nil
+	}
+
+	return nil, fmt.Errorf("cannot select %q from %s", n.children[0].name, t)
+}
+
+// project returns v projected to type t, which projectType gave for the type
+// of v.
+func (n *fields) project(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Zero(t), nil
+	}
+	if n.leaf() {
+		return v, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		elem, err := n.project(v.Elem(), t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(t.Elem())
+		out.Elem().Set(elem)
+		return out, nil
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		inner := v.Elem()
+		it, err := n.projectType(inner.Type())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		pv, err := n.project(inner, it)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(t).Elem()
+		out.Set(pv)
+		return out, nil
+
+	case reflect.Slice, reflect.Array:
+		if i, ok := n.index(); ok {
+			if i < 0 || i >= v.Len() {
+				return reflect.Zero(t), nil
+			}
+			return n.children[0].project(v.Index(i), t)
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		out := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := n.project(v.Index(i), t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(t), nil
+		}
+		out := reflect.MakeMap(t)
+		for _, c := range n.children {
+			key, err := mapKey(v.Type().Key(), c.name)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			mv := v.MapIndex(key)
+			if !mv.IsValid() {
+				continue
+			}
+			ct, err := c.projectType(v.Type().Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			pv, err := c.project(mv, ct)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.SetMapIndex(key, pv)
+		}
+		return out, nil
+
+	case reflect.Struct:
+		out := reflect.New(t).Elem()
+		for i, c := range n.children {
+			f, _ := field(v.Type(), c.name)
+			fv, err := v.FieldByIndexErr(f.Index)
+			if err != nil {
+				// A nil embedded pointer holds no fields.
+				continue
+			}
+			pv, err := c.project(fv, t.Field(i).Type)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Field(i).Set(pv)
+		}
+		return out, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot select %q from %s", n.children[0].name, v.Type())
+}
@@ -0,0 +1,458 @@
+package query
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A filter decides whether a value is kept. Filters are written as
+// comparisons of paths with literals, such as category == "Daf Yomi",
+// joined with && and ||, negated with ! and grouped with parentheses.
+type filter interface {
+	match(v reflect.Value) (bool, error)
+}
+
+type (
+	orFilter  struct{ l, r filter }
+	andFilter struct{ l, r filter }
+	notFilter struct{ f filter }
+
+	// existsFilter keeps values with a value other than the zero one at the
+	// path.
+	existsFilter struct{ path []string }
+
+	// compareFilter keeps values with a value at the path that compares to
+	// the literal lit as op asks. A null literal is nil.
+	compareFilter struct {
+		path []string
+		op   string
+		lit  any
+		re   *regexp.Regexp
+	}
+)
+
+func (f orFilter) match(v reflect.Value) (bool, error) {
+	ok, err := f.l.match(v)
+	if err != nil || ok {
+		return ok, err
+	}
+	return f.r.match(v)
+}
+
+func (f andFilter) match(v reflect.Value) (bool, error) {
+	ok, err := f.l.match(v)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return f.r.match(v)
+}
+
+func (f notFilter) match(v reflect.Value) (bool, error) {
+	ok, err := f.f.match(v)
+	return !ok, err
+}
+
+func (f existsFilter) match(v reflect.Value) (bool, error) {
+	values, err := resolve(v, f.path)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range values {
+		if truthy(v) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// match reports whether any of the values at the path compares as asked,
+// looking into a slice for a path that ends at one. A != comparison holds
+// when none of them is equal to the literal, so that it is always the
+// opposite of ==.
+func (f compareFilter) match(v reflect.Value) (bool, error) {
+	found, err := resolve(v, f.path)
+	if err != nil {
+		return false, err
+	}
+	var values []reflect.Value
+	for _, v := range found {
+		if e := indirect(v); e.IsValid() && (e.Kind() == reflect.Slice || e.Kind() == reflect.Array) {
+			for i := 0; i < e.Len(); i++ {
+				values = append(values, e.Index(i))
+			}
+			continue
+		}
+		values = append(values, v)
+	}
+
+	op := f.op
+	if op == "!=" {
+		op = "=="
+	}
+	ok := false
+	if f.lit == nil {
+		ok = len(values) == 0
+	}
+	for _, v := range values {
+		if f.compare(indirect(v), op) {
+			ok = true
+			break
+		}
+	}
+	return ok != (f.op == "!="), nil
+}
+
+func (f compareFilter) compare(v reflect.Value, op string) bool {
+	switch lit := f.lit.(type) {
+	case nil:
+		return !v.IsValid()
+	case bool:
+		return v.IsValid() && v.Kind() == reflect.Bool && v.Bool() == lit
+	case float64:
+		n, ok := number(v)
+		return ok && compares(cmp.Compare(n, lit), op)
+	case string:
+		if !v.IsValid() {
+			return false
+		}
+		s := text(v)
+		if op == "=~" {
+			return f.re.MatchString(s)
+		}
+		return compares(strings.Compare(s, lit), op)
+	}
+	return false
+}
+
+// compares reports whether the result c of a comparison satisfies op.
+func compares(c int, op string) bool {
+	switch op {
+	case "==":
+		return c == 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// truthy reports whether v is set: not nil, empty or the zero value.
+func truthy(v reflect.Value) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() > 0
+	}
+	return !v.IsZero()
+}
+
+// number returns v as a number, parsing it if it is a string.
+func number(v reflect.Value) (float64, bool) {
+	if !v.IsValid() {
+		return 0, false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Bool:
+		// Sefaria gives some flags as 0 and 1.
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	case reflect.String:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// text returns v as it is compared to a string: strings as they are, values
+// that marshal to a JSON string as that string, such as dates, and anything
+// else as it prints.
+func text(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	if !v.CanInterface() {
+		return ""
+	}
+	i := v.Interface()
+	if m, ok := i.(json.Marshaler); ok {
+		var s string
+		if data, err := m.MarshalJSON(); err == nil && json.Unmarshal(data, &s) == nil {
+			return s
+		}
+	}
+	return fmt.Sprint(i)
+}
+
+// parseFilter parses a filter expression.
+func parseFilter(s string) (filter, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s in filter", t)
+	}
+	return f, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokPath
+	tokString
+	tokNumber
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of filter"
+	}
+	return strconv.Quote(t.text)
+}
+
+// comparisons are the operators that compare a path with a literal.
+var comparisons = []string{"==", "!=", "<", "<=", ">", ">=", "=~"}
+
+// ops are the operators of the filter language, longest first so that <=
+// is not read as <.
+var ops = []string{"==", "!=", "<=", ">=", "=~", "&&", "||", "<", ">", "!", "(", ")"}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(s) && s[end] != c {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string in filter %q", s)
+			}
+			lit := s[i+1 : end]
+			if c == '"' {
+				var err error
+				if lit, err = strconv.Unquote(s[i : end+1]); err != nil {
+					return nil, fmt.Errorf("invalid string %s in filter", s[i:end+1])
+				}
+			}
+			toks = append(toks, token{tokString, lit})
+			i = end + 1
+
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && (s[end] == '.' || (s[end] >= '0' && s[end] <= '9')) {
+				end++
+			}
+			toks = append(toks, token{tokNumber, s[i:end]})
+			i = end
+
+		case c == '_' || unicode.IsLetter(rune(c)) || c >= 0x80:
+			end := i
+			for end < len(s) {
+				if s[end] == '[' {
+					_, n, err := bracket(s[end:])
+					if err != nil {
+						return nil, fmt.Errorf("%w in filter %q", err, s)
+					}
+					end += n
+					continue
+				}
+				if strings.ContainsRune(" \t\r\n=!<>&|()\"'", rune(s[end])) {
+					break
+				}
+				end++
+			}
+			toks = append(toks, token{tokPath, s[i:end]})
+			i = end
+
+		default:
+			op := ""
+			for _, o := range ops {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q in filter %q", c, s)
+			}
+			toks = append(toks, token{tokOp, op})
+			i += len(op)
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.toks) {
+		return token{kind: tokEOF}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) or() (filter, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = orFilter{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) and() (filter, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l = andFilter{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) unary() (filter, error) {
+	if p.accept("!") {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notFilter{f}, nil
+	}
+	if p.accept("(") {
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ) in filter, found %s", p.peek())
+		}
+		return f, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (filter, error) {
+	t := p.next()
+	if t.kind != tokPath {
+		return nil, fmt.Errorf("expected a field in filter, found %s", t)
+	}
+	path, err := parsePath(t.text)
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	if op.kind != tokOp || !slices.Contains(comparisons, op.text) {
+		return existsFilter{path}, nil
+	}
+	p.pos++
+
+	f := compareFilter{path: path, op: op.text}
+	switch lit := p.next(); {
+	case lit.kind == tokString:
+		f.lit = lit.text
+	case lit.kind == tokNumber:
+		n, err := strconv.ParseFloat(lit.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s in filter", lit)
+		}
+		f.lit = n
+	case lit.kind == tokPath && (lit.text == "true" || lit.text == "false"):
+		f.lit = lit.text == "true"
+	case lit.kind == tokPath && lit.text == "null":
+		f.lit = nil
+	default:
+		return nil, fmt.Errorf("expected a value after %s in filter, found %s", op.text, lit)
+	}
+
+	switch f.lit.(type) {
+	case string:
+		if f.op == "=~" {
+			if f.re, err = regexp.Compile(f.lit.(string)); err != nil {
+				return nil, fmt.Errorf("invalid pattern in filter: %w", err)
+			}
+		}
+	case float64:
+		if f.op == "=~" {
+			return nil, fmt.Errorf("=~ needs a pattern, found %v", f.lit)
+		}
+	default:
+		if f.op != "==" && f.op != "!=" {
+			return nil, fmt.Errorf("%s can only compare numbers and strings", f.op)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,195 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownField is returned for a path naming a field a value does not
+// have.
+var ErrUnknownField = errors.New("unknown field")
+
+// parsePath splits a path such as title.en, haftarah.0.ref or
+// titles["Genesis 1"] into its segments. Segments are separated by dots, or
+// given in brackets, quoted when they hold dots or brackets themselves.
+func parsePath(s string) ([]string, error) {
+	var segs []string
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			continue
+		case '[':
+			seg, n, err := bracket(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w in path %q", err, s)
+			}
+			segs = append(segs, seg)
+			i += n
+		default:
+			end := strings.IndexAny(s[i:], ".[")
+			if end < 0 {
+				end = len(s) - i
+			}
+			segs = append(segs, strings.TrimSpace(s[i:i+end]))
+			i += end
+		}
+	}
+
+	for _, seg := range segs {
+		if seg == "" {
+			return nil, fmt.Errorf("empty segment in path %q", s)
+		}
+	}
+	if len(segs) == 0 {
+		return nil, errors.New("empty path")
+	}
+	return segs, nil
+}
+
+// bracket reads the segment given in brackets at the start of s, such as
+// [0] or ["Genesis 1"]. It returns the segment and the length of the brackets.
+func bracket(s string) (string, int, error) {
+	if len(s) > 1 && (s[1] == '"' || s[1] == '\'') {
+		end := strings.IndexByte(s[2:], s[1])
+		if end < 0 {
+			return "", 0, errors.New("unterminated quote")
+		}
+		n := 2 + end + 1
+		if n >= len(s) || s[n] != ']' {
+			return "", 0, errors.New("missing ]")
+		}
+		return s[2 : 2+end], n + 1, nil
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return "", 0, errors.New("missing ]")
+	}
+	return strings.TrimSpace(s[1:end]), end + 1, nil
+}
+
+// resolve returns the values found at the path segs under v. Slices are
+// searched element by element unless a segment gives an index, so a path may
+// lead to many values, or none.
+func resolve(v reflect.Value, segs []string) ([]reflect.Value, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if len(segs) == 0 {
+		return []reflect.Value{v}, nil
+	}
+
+	seg := segs[0]
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if i, err := strconv.Atoi(seg); err == nil {
+			if i < 0 || i >= v.Len() {
+				return nil, nil
+			}
+			return resolve(v.Index(i), segs[1:])
+		}
+		var out []reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			found, err := resolve(v.Index(i), segs)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, found...)
+		}
+		return out, nil
+
+	case reflect.Map:
+		key, err := mapKey(v.Type().Key(), seg)
+		if err != nil {
+			return nil, err
+		}
+		return resolve(v.MapIndex(key), segs[1:])
+
+	case reflect.Struct:
+		f, ok := field(v.Type(), seg)
+		if !ok {
+			return nil, fmt.Errorf("%w %q in %s", ErrUnknownField, seg, v.Type())
+		}
+		fv, err := v.FieldByIndexErr(f.Index)
+		if err != nil {
+			// A nil embedded pointer holds no fields.
+			return nil, nil
+		}
+		return resolve(fv, segs[1:])
+	}
+
+	return nil, fmt.Errorf("cannot select %q from %s", seg, v.Type())
+}
+
+// field finds the field of struct type t called name, by the name it is
+// given in JSON or by its own, ignoring case. Fields of embedded structs are
+// found as well, as they are promoted in JSON.
+func field(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		json, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if json == "-" {
+			continue
+		}
+
+		if f.Anonymous && json == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if inner, ok := field(ft, name); ok {
+					inner.Index = append([]int{i}, inner.Index...)
+					return inner, true
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if strings.EqualFold(json, name) || strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// mapKey converts a path segment to a key of a map with keys of type t.
+func mapKey(t reflect.Type, seg string) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(seg).Convert(t), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(seg, 10, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("map key %q is not a number", seg)
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(seg, 10, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("map key %q is not a number", seg)
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot select %q from a map keyed by %s", seg, t)
+}
+
+// indirect follows pointers and interfaces to the value they hold. It
+// returns the zero Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+// Package query narrows down values before they are rendered: a filter
+// keeps only the values that match it, and a projection keeps only the
+// fields that were asked for.
+//
+// Both name the values they look at by paths. A path is a list of segments
+// separated by dots, such as title.en. A segment names a field of a struct,
+// by its JSON name or its Go name; a key of a map; or an index of a slice.
+// Segments that hold dots may be given in brackets, such as
+// versions["Tanakh: The Holy Scriptures"]. A segment naming a field of a
+// slice's elements applies to each of them, so haftarah.ref names the refs
+// of all of the haftarot.
+package query
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Query is a filter and a projection, applied in that order.
+type Query struct {
+	filter filter
+	fields *fields
+}
+
+// Parse parses a filter expression and a comma separated list of paths to
+// project values to. It returns nil when both are empty, as there is
+// nothing to do.
+//
+// Filters compare paths with literals, as in category == "Daf Yomi". Strings
+// are quoted, and numbers, true, false and null are written as they are.
+// The comparisons are ==, !=, <, <=, >, >= and =~, which matches a regular
+// expression. A path on its own keeps values that have it set. Filters are
+// joined with && and ||, negated with ! and grouped with parentheses.
+func Parse(fields, filter string) (*Query, error) {
+	if fields == "" && filter == "" {
+		return nil, nil
+	}
+
+	q := &Query{}
+	if filter != "" {
+		f, err := parseFilter(filter)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse filter: %w", err)
+		}
+		q.filter = f
+	}
+	if fields != "" {
+		f, err := parseFields(fields)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse fields: %w", err)
+		}
+		q.fields = f
+	}
+	return q, nil
+}
+
+// Apply filters and projects v. The elements of a slice are filtered one by
+// one; any other value is kept whole or not at all, in which case Apply
+// returns nil.
+func (q *Query) Apply(v any) (any, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return v, nil
+	}
+
+	if q.filter != nil {
+		var (
+			keep bool
+			err  error
+		)
+		rv, keep, err = q.filterValue(rv)
+		if err != nil {
+			return nil, fmt.Errorf("cannot filter: %w", err)
+		}
+		if !keep {
+			return nil, nil
+		}
+	}
+
+	if q.fields != nil {
+		t, err := q.fields.projectType(rv.Type())
+		if err != nil {
+			return nil, fmt.Errorf("cannot select fields: %w", err)
+		}
+		if rv, err = q.fields.project(rv, t); err != nil {
+			return nil, fmt.Errorf("cannot select fields: %w", err)
+		}
+	}
+	return rv.Interface(), nil
+}
+
+// filterValue returns the elements of a slice that match the filter, or the
+// value itself and whether it matches.
+func (q *Query) filterValue(v reflect.Value) (reflect.Value, bool, error) {
+	elems := indirect(v)
+	if !elems.IsValid() || (elems.Kind() != reflect.Slice && elems.Kind() != reflect.Array) {
+		ok, err := q.filter.match(v)
+		return v, ok, err
+	}
+
+	out := reflect.MakeSlice(reflect.SliceOf(elems.Type().Elem()), 0, elems.Len())
+	for i := 0; i < elems.Len(); i++ {
+		ok, err := q.filter.match(elems.Index(i))
+		if err != nil {
+			return v, false, err
+		}
+		if ok {
+			out = reflect.Append(out, elems.Index(i))
+		}
+	}
+	return out, true, nil
+}
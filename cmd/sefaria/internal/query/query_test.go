@@ -0,0 +1,511 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type title struct {
+	En string `json:"en"`
+	He string `json:"he"`
+}
+
+type reading struct {
+	Ref   string `json:"ref"`
+	Order int    `json:"order"`
+}
+
+type learning struct {
+	Title    title             `json:"title"`
+	Category string            `json:"category"`
+	HeRef    string            `json:"heRef"`
+	Order    int               `json:"order"`
+	Daf      string            `json:"daf"`
+	Weekly   bool              `json:"weekly"`
+	Note     *string           `json:"note"`
+	Aliyot   []string          `json:"aliyot"`
+	Haftarah []reading         `json:"haftarah"`
+	Versions map[string]string `json:"versions"`
+	Pages    map[int]string    `json:"pages"`
+	Extra    any               `json:"extra"`
+	Hidden   string            `json:"-"`
+}
+
+func note(s string) *string { return &s }
+
+var learnings = []learning{
+	{
+		Title:    title{En: "Parashat Hashavua", He: "פרשת השבוע"},
+		Category: "Tanakh",
+		HeRef:    "בראשית א׳:א׳-ו׳:ח׳",
+		Order:    1,
+		Daf:      "9",
+		Weekly:   true,
+		Aliyot:   []string{"Genesis 1:1-2:3", "Genesis 2:4-19"},
+		Haftarah: []reading{{Ref: "Isaiah 42:5-43:10", Order: 1}},
+		Versions: map[string]string{"english": "The Contemporary Torah", "Tanakh: The Holy Scriptures, 1917": "JPS"},
+		Pages:    map[int]string{1: "one"},
+		Extra:    map[string]any{"aliyah": float64(3)},
+	},
+	{
+		Title:    title{En: "Daf Yomi", He: "דף יומי"},
+		Category: "Talmud",
+		Order:    2,
+		Daf:      "10",
+		Note:     note("a new tractate"),
+	},
+	{
+		Title:    title{En: "Mishnah Yomit", He: "משנה יומית"},
+		Category: "Mishnah",
+		Order:    3,
+		Haftarah: []reading{{Ref: "Jeremiah 1:1", Order: 1}, {Ref: "Jeremiah 2:4", Order: 2}},
+	},
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+		err  string
+	}{
+		{path: "title", want: []string{"title"}},
+		{path: "title.en", want: []string{"title", "en"}},
+		{path: "haftarah.0.ref", want: []string{"haftarah", "0", "ref"}},
+		{path: "haftarah[0].ref", want: []string{"haftarah", "0", "ref"}},
+		{path: "haftarah[ 0 ]", want: []string{"haftarah", "0"}},
+		{path: `versions["Tanakh: The Holy Scriptures, 1917"]`, want: []string{"versions", "Tanakh: The Holy Scriptures, 1917"}},
+		{path: `versions['a.b[c]'].x`, want: []string{"versions", "a.b[c]", "x"}},
+		{path: `["title"]["en"]`, want: []string{"title", "en"}},
+		{path: "title..en.", want: []string{"title", "en"}},
+		{path: "", err: "empty path"},
+		{path: ".", err: "empty path"},
+		{path: "title[", err: `missing ] in path "title["`},
+		{path: "title[0", err: `missing ] in path "title[0"`},
+		{path: `title["en]`, err: `unterminated quote in path "title[\"en]"`},
+		{path: `title["en"x]`, err: `missing ] in path "title[\"en\"x]"`},
+		{path: "title[]", err: `empty segment in path "title[]"`},
+		{path: "title. .en", err: `empty segment in path "title. .en"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parsePath(tt.path)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("parsePath(%q) error = %v, want %q", tt.path, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePath(%q) error = %v", tt.path, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parsePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Fields(t *testing.T) {
+	tests := []struct {
+		fields string
+		err    string
+	}{
+		{fields: "title.en,category"},
+		{fields: `versions["a, b"],title`},
+		{fields: " , ", err: "cannot parse fields: no fields given"},
+		{fields: "title[", err: `cannot parse fields: missing ] in path "title["`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fields, func(t *testing.T) {
+			_, err := Parse(tt.fields, "")
+			if got := errText(err); got != tt.err {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.fields, got, tt.err)
+			}
+		})
+	}
+
+	if q, err := Parse("", ""); q != nil || err != nil {
+		t.Errorf(`Parse("", "") = %v, %v, want nil, nil`, q, err)
+	}
+}
+
+// project applies fields to v and returns the result as JSON.
+func project(t *testing.T, fields string, v any) string {
+	t.Helper()
+	q, err := Parse(fields, "")
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", fields, err)
+	}
+	out, err := q.Apply(v)
+	if err != nil {
+		t.Fatalf("Apply(%q) error = %v", fields, err)
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("cannot marshal %#v: %v", out, err)
+	}
+	return string(data)
+}
+
+func TestQuery_Project(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		v      any
+		want   string
+	}{
+		{
+			name:   "nested struct",
+			fields: "title.en",
+			v:      learnings[0],
+			want:   `{"title":{"en":"Parashat Hashavua"}}`,
+		},
+		{
+			name:   "JSON names",
+			fields: "heRef,title",
+			v:      learnings[0],
+			want:   `{"heRef":"בראשית א׳:א׳-ו׳:ח׳","title":{"en":"Parashat Hashavua","he":"פרשת השבוע"}}`,
+		},
+		{
+			name:   "Go names",
+			fields: "HeRef,Title.He",
+			v:      learnings[0],
+			want:   `{"heRef":"בראשית א׳:א׳-ו׳:ח׳","title":{"he":"פרשת השבוע"}}`,
+		},
+		{
+			name:   "order of the fields given",
+			fields: "order,title.he,category,title.en",
+			v:      learnings[1],
+			want:   `{"order":2,"title":{"he":"דף יומי","en":"Daf Yomi"},"category":"Talmud"}`,
+		},
+		{
+			name:   "slice",
+			fields: "title.en,order",
+			v:      learnings,
+			want:   `[{"title":{"en":"Parashat Hashavua"},"order":1},{"title":{"en":"Daf Yomi"},"order":2},{"title":{"en":"Mishnah Yomit"},"order":3}]`,
+		},
+		{
+			name:   "field of slice elements",
+			fields: "haftarah.ref",
+			v:      learnings[2],
+			want:   `{"haftarah":[{"ref":"Jeremiah 1:1"},{"ref":"Jeremiah 2:4"}]}`,
+		},
+		{
+			name:   "index",
+			fields: "haftarah.1.ref,aliyot[0]",
+			v:      &learnings[2],
+			want:   `{"haftarah":{"ref":"Jeremiah 2:4"},"aliyot":""}`,
+		},
+		{
+			name:   "index of a slice",
+			fields: "1.title.en",
+			v:      learnings,
+			want:   `{"title":{"en":"Daf Yomi"}}`,
+		},
+		{
+			name:   "index out of range",
+			fields: "haftarah.5.ref",
+			v:      learnings[0],
+			want:   `{"haftarah":{"ref":""}}`,
+		},
+		{
+			name:   "map keys",
+			fields: `versions["Tanakh: The Holy Scriptures, 1917"],versions.missing,pages.1`,
+			v:      learnings[0],
+			want:   `{"versions":{"Tanakh: The Holy Scriptures, 1917":"JPS"},"pages":{"1":"one"}}`,
+		},
+		{
+			name:   "interface",
+			fields: "extra.aliyah",
+			v:      learnings[0],
+			want:   `{"extra":{"aliyah":3}}`,
+		},
+		{
+			name:   "nil pointer",
+			fields: "note",
+			v:      learnings[0],
+			want:   `{"note":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := project(t, tt.fields, tt.v); got != tt.want {
+				t.Errorf("project %q =\n%s\nwant\n%s", tt.fields, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_ProjectErrors(t *testing.T) {
+	tests := []struct {
+		fields string
+		err    string
+	}{
+		{"nope", `cannot select fields: unknown field "nope" in query.learning`},
+		{"hidden", `cannot select fields: unknown field "hidden" in query.learning`},
+		{"title,Title.en", `cannot select fields: field "Title" of query.learning is selected twice`},
+		{"category.name", `cannot select fields: cannot select "name" from string`},
+		{"pages.one", `cannot select fields: map key "one" is not a number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fields, func(t *testing.T) {
+			q, err := Parse(tt.fields, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = q.Apply(learnings[0])
+			if got := errText(err); got != tt.err {
+				t.Errorf("Apply(%q) error = %q, want %q", tt.fields, got, tt.err)
+			}
+		})
+	}
+
+	q, _ := Parse("nope", "")
+	if _, err := q.Apply(learnings[0]); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("Apply() error = %v, want ErrUnknownField", err)
+	}
+}
+
+// filtered returns the English titles of the learnings that filter keeps.
+func filtered(t *testing.T, filter string) []string {
+	t.Helper()
+	q, err := Parse("", filter)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", filter, err)
+	}
+	out, err := q.Apply(learnings)
+	if err != nil {
+		t.Fatalf("Apply(%q) error = %v", filter, err)
+	}
+	titles := []string{}
+	for _, l := range out.([]learning) {
+		titles = append(titles, l.Title.En)
+	}
+	return titles
+}
+
+const (
+	parasha = "Parashat Hashavua"
+	daf     = "Daf Yomi"
+	mishnah = "Mishnah Yomit"
+)
+
+func TestQuery_Filter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		// Strings.
+		{`category == "Talmud"`, []string{daf}},
+		{`category == 'Talmud'`, []string{daf}},
+		{`category != "Talmud"`, []string{parasha, mishnah}},
+		{`title.en == "Daf Yomi"`, []string{daf}},
+		{`title.he == "דף יומי"`, []string{daf}},
+		{`category < "Talmud"`, []string{mishnah}},
+		{`category <= "Talmud"`, []string{daf, mishnah}},
+		{`category > "Mishnah"`, []string{parasha, daf}},
+		{`category >= "Talmud"`, []string{parasha, daf}},
+		{`title.en =~ "Yom(i|it)$"`, []string{daf, mishnah}},
+		{`title.en =~ "^daf"`, []string{}},
+		{`title.en =~ "(?i)^daf"`, []string{daf}},
+		{`category == "tanakh"`, []string{}},
+
+		// Numbers.
+		{`order == 2`, []string{daf}},
+		{`order != 2`, []string{parasha, mishnah}},
+		{`order < 2`, []string{parasha}},
+		{`order <= 2`, []string{parasha, daf}},
+		{`order > 1.5`, []string{daf, mishnah}},
+		{`order >= 3`, []string{mishnah}},
+		{`order > -1`, []string{parasha, daf, mishnah}},
+
+		// Strings holding numbers compare as numbers with a number, and as
+		// strings with a string.
+		{`daf > 9`, []string{daf}},
+		{`daf < 10`, []string{parasha}},
+		{`daf > "9"`, []string{}},
+		{`daf < "9"`, []string{daf, mishnah}},
+		{`order == "2"`, []string{daf}},
+
+		// Booleans.
+		{`weekly == true`, []string{parasha}},
+		{`weekly == false`, []string{daf, mishnah}},
+		{`weekly != true`, []string{daf, mishnah}},
+
+		// Null and missing values.
+		{`note == null`, []string{parasha, mishnah}},
+		{`note != null`, []string{daf}},
+		{`aliyot == null`, []string{daf, mishnah}},
+		{`aliyot != null`, []string{parasha}},
+		{`versions.english == null`, []string{daf, mishnah}},
+		{`versions.english != null`, []string{parasha}},
+		{`versions.english == "The Contemporary Torah"`, []string{parasha}},
+		{`versions.english != "The Contemporary Torah"`, []string{daf, mishnah}},
+		{`extra.aliyah == 3`, []string{parasha}},
+		{`extra.aliyah == null`, []string{daf, mishnah}},
+		{`note == "a new tractate"`, []string{daf}},
+		{`haftarah.ref == null`, []string{daf}},
+
+		// Slices match when any of their values do.
+		{`aliyot == "Genesis 2:4-19"`, []string{parasha}},
+		{`haftarah.ref =~ "^Jeremiah"`, []string{mishnah}},
+		{`haftarah.order == 2`, []string{mishnah}},
+		{`haftarah.order != 2`, []string{parasha, daf}},
+		{`haftarah.1.ref == "Jeremiah 2:4"`, []string{mishnah}},
+		{`haftarah[0].order == 1`, []string{parasha, mishnah}},
+
+		// Paths on their own.
+		{`note`, []string{daf}},
+		{`haftarah`, []string{parasha, mishnah}},
+		{`!weekly`, []string{daf, mishnah}},
+		{`versions["Tanakh: The Holy Scriptures, 1917"]`, []string{parasha}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			if got := filtered(t, tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("filter %s kept %q, want %q", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_FilterLogic(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{`order > 1 && category != "Mishnah"`, []string{daf}},
+		{`order == 1 || order == 3`, []string{parasha, mishnah}},
+
+		// && binds tighter than ||.
+		{`order == 1 || order == 2 && category == "Mishnah"`, []string{parasha}},
+		{`order == 2 && category == "Mishnah" || order == 1`, []string{parasha}},
+		{`(order == 1 || order == 2) && category == "Talmud"`, []string{daf}},
+		{`order == 1 || (order == 2 && category == "Talmud")`, []string{parasha, daf}},
+
+		// ! binds tighter than both.
+		{`!weekly && order < 3`, []string{daf}},
+		{`!(weekly || order == 3)`, []string{daf}},
+		{`!weekly || order == 1`, []string{parasha, daf, mishnah}},
+		{`!!weekly`, []string{parasha}},
+		{`! category == "Talmud"`, []string{parasha, mishnah}},
+
+		{`((order == 2))`, []string{daf}},
+		{"order == 1\n\t||\r\norder == 2", []string{parasha, daf}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			if got := filtered(t, tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("filter %s kept %q, want %q", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_FilterValue(t *testing.T) {
+	q, err := Parse("", `category == "Talmud"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Values other than slices are kept whole or not at all.
+	if got, err := q.Apply(learnings[1]); err != nil || got == nil {
+		t.Errorf("Apply(matching) = %v, %v, want the value", got, err)
+	}
+	if got, err := q.Apply(&learnings[0]); err != nil || got != nil {
+		t.Errorf("Apply(not matching) = %v, %v, want nil", got, err)
+	}
+	if got, err := q.Apply(nil); err != nil || got != nil {
+		t.Errorf("Apply(nil) = %v, %v, want nil", got, err)
+	}
+
+	// Filtering comes before projection.
+	q, err = Parse("title.en", "order >= 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := q.Apply(learnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(out)
+	if want := `[{"title":{"en":"Daf Yomi"}},{"title":{"en":"Mishnah Yomit"}}]`; string(data) != want {
+		t.Errorf("Apply() = %s, want %s", data, want)
+	}
+}
+
+func TestParse_FilterErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		// Lexer errors.
+		{`category == "Talmud`, `unterminated string in filter "category == \"Talmud"`},
+		{`category == 'Talmud`, `unterminated string in filter "category == 'Talmud"`},
+		{`category == "\q"`, `invalid string "\q" in filter`},
+		{`category @ "Talmud"`, `unexpected '@' in filter "category @ \"Talmud\""`},
+		{`order = 1`, `unexpected '=' in filter "order = 1"`},
+		{`order == 1 & order == 2`, `unexpected '&' in filter "order == 1 & order == 2"`},
+		{`versions["a == 1`, `unterminated quote in filter "versions[\"a == 1"`},
+		{`versions[a == 1`, `missing ] in filter "versions[a == 1"`},
+
+		// Parser errors.
+		{`== 1`, `expected a field in filter, found "=="`},
+		{`"Talmud" == category`, `expected a field in filter, found "Talmud"`},
+		{`category ==`, `expected a value after == in filter, found end of filter`},
+		{`category == other`, `expected a value after == in filter, found "other"`},
+		{`order == 1.2.3`, `invalid number "1.2.3" in filter`},
+		{`(order == 1`, `expected ) in filter, found end of filter`},
+		{`order == 1)`, `unexpected ")" in filter`},
+		{`order == 1 order == 2`, `unexpected "order" in filter`},
+		{`order == 1 ||`, `expected a field in filter, found end of filter`},
+		{`!`, `expected a field in filter, found end of filter`},
+		{`title.en =~ "("`, "invalid pattern in filter: error parsing regexp: missing closing ): `(`"},
+		{`order =~ 1`, `=~ needs a pattern, found 1`},
+		{`weekly < true`, `< can only compare numbers and strings`},
+		{`note >= null`, `>= can only compare numbers and strings`},
+		{`title[].en`, `empty segment in path "title[].en"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			_, err := Parse("", tt.filter)
+			if got, want := errText(err), "cannot parse filter: "+tt.err; got != want {
+				t.Errorf("Parse(%q) error =\n%s\nwant\n%s", tt.filter, got, want)
+			}
+		})
+	}
+}
+
+func TestQuery_FilterApplyErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		{`nope == 1`, `cannot filter: unknown field "nope" in query.learning`},
+		{`title.nope`, `cannot filter: unknown field "nope" in query.title`},
+		{`category.name == "x"`, `cannot filter: cannot select "name" from string`},
+		{`pages.one == "x"`, `cannot filter: map key "one" is not a number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			q, err := Parse("", tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = q.Apply(learnings)
+			if got := errText(err); got != tt.err {
+				t.Errorf("Apply(%q) error = %q, want %q", tt.filter, got, tt.err)
+			}
+		})
+	}
+}
+
+func errText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return strings.TrimSpace(err.Error())
+}
@@ -0,0 +1,124 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// columnsWidth is the narrowest terminal the bilingual renderer shows side by
+// side. Narrower terminals get the English and Hebrew of each segment one
+// after the other.
+const columnsWidth = 60
+
+// gutter separates the English column from the Hebrew one.
+const gutter = " │ "
+
+// BilingualRenderer shows texts with their English on the left and their
+// Hebrew on the right, aligned by segment, each column wrapped on its own.
+// Values other than texts and segment pairs are rendered as text.
+type BilingualRenderer struct {
+	w    io.Writer
+	opts Options
+	text *TextRenderer
+}
+
+func NewBilingualRenderer(w io.Writer, opts Options) *BilingualRenderer {
+	return &BilingualRenderer{
+		w:    w,
+		opts: opts,
+		text: NewTextRenderer(w, opts),
+	}
+}
+
+func (r *BilingualRenderer) Render(v any) error {
+	switch v := v.(type) {
+	case *sefaria.Text:
+		if v == nil {
+			return r.text.Render(v)
+		}
+		r.renderText(v)
+	case sefaria.Text:
+		r.renderText(&v)
+	case iter.Seq2[sefaria.Segment, sefaria.Segment]:
+		r.renderSegments(v)
+	default:
+		return r.text.Render(v)
+	}
+	return nil
+}
+
+func (r *BilingualRenderer) Flush() error { return nil }
+
+func (r *BilingualRenderer) renderText(t *sefaria.Text) {
+	r.renderPair(t.Ref, t.HeRef)
+	fmt.Fprintln(r.w)
+	r.renderSegments(t.SegmentPairs())
+}
+
+func (r *BilingualRenderer) renderSegments(segments iter.Seq2[sefaria.Segment, sefaria.Segment]) {
+	first := true
+	for en, he := range segments {
+		if !first {
+			fmt.Fprintln(r.w)
+		}
+		first = false
+		r.renderPair(en.Text, he.Text)
+	}
+}
+
+// renderPair writes the English and Hebrew of a segment side by side, or one
+// after the other when the terminal is too narrow for both.
+func (r *BilingualRenderer) renderPair(en, he string) {
+	enDir, heDir := direction(en, bidi.LeftToRight), direction(he, bidi.RightToLeft)
+	if r.opts.Width < columnsWidth {
+		r.writeLines(r.layout(r.opts.Width, enDir).Lines(en))
+		r.writeLines(r.layout(r.opts.Width, heDir).Lines(he))
+		return
+	}
+
+	width := (r.opts.Width - bidi.StringWidth(gutter)) / 2
+	left := r.layout(width, enDir).Lines(en)
+	right := r.layout(width, heDir).Lines(he)
+	for i := range max(len(left), len(right)) {
+		var line strings.Builder
+		if i < len(left) {
+			line.WriteString(left[i])
+		}
+		line.WriteString(strings.Repeat(" ", max(0, width-bidi.StringWidth(line.String()))))
+		line.WriteString(gutter)
+		if i < len(right) {
+			line.WriteString(right[i])
+		}
+		fmt.Fprintln(r.w, strings.TrimRight(line.String(), " "))
+	}
+}
+
+// direction returns the direction of s, or fallback when s has no letters to
+// tell it by. A translation shown in the Hebrew column, such as a Yiddish or
+// Arabic one, keeps its own direction.
+func direction(s string, fallback bidi.Dir) bidi.Dir {
+	if d := bidi.Direction(s); d != bidi.Auto {
+		return d
+	}
+	return fallback
+}
+
+func (r *BilingualRenderer) layout(width int, dir bidi.Dir) bidi.Layout {
+	return bidi.Layout{Width: width, Dir: dir, Logical: !r.opts.Bidi}
+}
+
+// writeLines writes lines, leaving out the single empty line of a side that
+// has no text.
+func (r *BilingualRenderer) writeLines(lines []string) {
+	if len(lines) == 1 && strings.TrimSpace(lines[0]) == "" {
+		return
+	}
+	for _, line := range lines {
+		fmt.Fprintln(r.w, line)
+	}
+}
@@ -0,0 +1,190 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// genesis has an English segment that wraps to more lines than its Hebrew,
+// followed by a Hebrew segment that wraps to more lines than its English.
+var genesis = &sefaria.Text{
+	Ref:   "Genesis 1:1-2",
+	HeRef: "בראשית א׳:א׳-ב׳",
+	Text: []string{
+		"When God began to create heaven and earth—the earth being unformed and void, with darkness over the surface of the deep",
+		"and a wind from God",
+	},
+	He: []string{
+		"בראשית ברא אלהים",
+		"והארץ היתה תהו ובהו וחשך על פני תהום ורוח אלהים מרחפת על פני המים",
+	},
+}
+
+func renderBilingual(t *testing.T, v any, opts Options) []string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := NewBilingualRenderer(&b, opts).Render(v); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
+
+// blocks splits lines into the blocks of each segment, which are separated by
+// blank lines.
+func blocks(lines []string) [][]string {
+	var out [][]string
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i == len(lines) || lines[i] == "" {
+			out = append(out, lines[start:i])
+			start = i + 1
+		}
+	}
+	return out
+}
+
+// columns splits a side by side line at the gutter, checking that the gutter
+// is where it belongs.
+func columns(t *testing.T, line string, width int) (left, right string) {
+	t.Helper()
+	left, right, ok := strings.Cut(line, strings.TrimRight(gutter, " "))
+	if !ok {
+		t.Fatalf("no gutter in %q", line)
+	}
+	if got := bidi.StringWidth(left); got != width {
+		t.Errorf("gutter of %q at column %d, want %d", line, got, width)
+	}
+	return strings.TrimSpace(left), strings.TrimSpace(right)
+}
+
+func TestBilingualRenderer_SideBySide(t *testing.T) {
+	const width = 60
+	column := (width - bidi.StringWidth(gutter)) / 2
+
+	segments := blocks(renderBilingual(t, genesis, Options{Width: width}))
+	if len(segments) != 3 {
+		t.Fatalf("got %d blocks, want the ref and 2 segments:\n%q", len(segments), segments)
+	}
+
+	wants := []struct{ en, he string }{
+		{genesis.Ref, genesis.HeRef},
+		{genesis.Text[0], genesis.He[0]},
+		{genesis.Text[1], genesis.He[1]},
+	}
+	for i, block := range segments {
+		var en, he []string
+		for _, line := range block {
+			if w := bidi.StringWidth(line); w > width {
+				t.Errorf("line %q is %d columns wide, want at most %d", line, w, width)
+			}
+			left, right := columns(t, line, column)
+			if left != "" {
+				en = append(en, left)
+			}
+			if right != "" {
+				he = append(he, right)
+			}
+		}
+
+		// Each column starts on the first line of its block, and the block
+		// is as long as the longer of them.
+		if len(en) == 0 || len(he) == 0 {
+			t.Fatalf("block %d is missing a side: %q", i, block)
+		}
+		if left, right := columns(t, block[0], column); left != en[0] || right != he[0] {
+			t.Errorf("block %d does not start both columns on its first line: %q", i, block)
+		}
+		if len(block) != max(len(en), len(he)) {
+			t.Errorf("block %d has %d lines, want %d", i, len(block), max(len(en), len(he)))
+		}
+		if got := strings.Join(en, " "); got != wants[i].en {
+			t.Errorf("block %d English = %q, want %q", i, got, wants[i].en)
+		}
+		if got := strings.Join(he, " "); got != wants[i].he {
+			t.Errorf("block %d Hebrew = %q, want %q", i, got, wants[i].he)
+		}
+	}
+
+	if en := len(segments[1]); en < 3 {
+		t.Errorf("first segment takes %d lines, want the English to wrap", en)
+	}
+	if he := len(segments[2]); he < 2 {
+		t.Errorf("second segment takes %d lines, want the Hebrew to wrap", he)
+	}
+}
+
+func TestBilingualRenderer_VisualOrder(t *testing.T) {
+	const width = 60
+	column := (width - bidi.StringWidth(gutter)) / 2
+
+	lines := renderBilingual(t, genesis, Options{Width: width, Bidi: true})
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		_, right, _ := strings.Cut(line, gutter)
+		if right == "" {
+			continue
+		}
+		// The Hebrew is laid out right to left, against the right edge of
+		// its column.
+		if w := bidi.StringWidth(right); w != column {
+			t.Errorf("Hebrew %q is %d columns wide, want it aligned right at %d", right, w, column)
+		}
+	}
+	if _, right, _ := strings.Cut(lines[0], gutter); strings.TrimSpace(right) != "׳ב-׳א:׳א תישארב" {
+		t.Errorf("Hebrew ref = %q, want it in visual order", right)
+	}
+}
+
+func TestBilingualRenderer_Narrow(t *testing.T) {
+	const width = 40
+
+	lines := renderBilingual(t, genesis, Options{Width: width})
+	for _, line := range lines {
+		if strings.Contains(line, strings.TrimSpace(gutter)) {
+			t.Errorf("narrow layout has a gutter: %q", line)
+		}
+		if w := bidi.StringWidth(line); w > width {
+			t.Errorf("line %q is %d columns wide, want at most %d", line, w, width)
+		}
+	}
+
+	segments := blocks(lines)
+	if len(segments) != 3 {
+		t.Fatalf("got %d blocks, want the ref and 2 segments:\n%q", len(segments), segments)
+	}
+	wants := []string{
+		genesis.Ref + " " + genesis.HeRef,
+		genesis.Text[0] + " " + genesis.He[0],
+		genesis.Text[1] + " " + genesis.He[1],
+	}
+	for i, block := range segments {
+		if got := strings.Join(block, " "); got != wants[i] {
+			t.Errorf("block %d = %q, want the English then the Hebrew: %q", i, got, wants[i])
+		}
+	}
+}
+
+func TestBilingualRenderer_MissingSide(t *testing.T) {
+	text := &sefaria.Text{
+		Ref:  "Genesis 1:1-2",
+		Text: []string{"In the beginning", "the earth"},
+		He:   []string{"בראשית"},
+	}
+
+	lines := renderBilingual(t, text.SegmentPairs(), Options{Width: 40})
+	want := []string{"In the beginning", "בראשית", "", "the earth"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("narrow layout = %q, want %q", lines, want)
+	}
+
+	lines = renderBilingual(t, text.SegmentPairs(), Options{Width: 60})
+	if len(lines) != 3 || lines[2] != "the earth                    │" {
+		t.Errorf("side by side layout = %q, want an empty Hebrew column for the second segment", lines)
+	}
+}
@@ -6,18 +6,22 @@ import (
 	"io"
 	"reflect"
 	"strings"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
 )
 
 type CSVRenderer struct {
 	w              io.Writer
 	cw             *csv.Writer
+	strategy       bidi.Strategy
 	headersWritten bool
 }
 
-func NewCSVRenderer(w io.Writer) *CSVRenderer {
+func NewCSVRenderer(w io.Writer, strategy bidi.Strategy) *CSVRenderer {
 	return &CSVRenderer{
-		w:  w,
-		cw: csv.NewWriter(w),
+		w:        w,
+		cw:       csv.NewWriter(w),
+		strategy: strategy,
 	}
 }
 
@@ -40,19 +44,22 @@ func (r *CSVRenderer) Render(v any) error {
 			}
 			for i := 0; i < val.Len(); i++ {
 				row := structToStringSlice(val.Index(i))
+				for j, cell := range row {
+					row[j] = r.strategy.Convert(cell)
+				}
 				if err := r.cw.Write(row); err != nil {
 					return err
 				}
 			}
 		default:
 			for i := 0; i < val.Len(); i++ {
-				if err := r.cw.Write([]string{fmt.Sprint(val.Index(i).Interface())}); err != nil {
+				if err := r.cw.Write([]string{r.strategy.Convert(fmt.Sprint(val.Index(i).Interface()))}); err != nil {
 					return err
 				}
 			}
 		}
 	default:
-		return r.cw.Write([]string{fmt.Sprint(v)})
+		return r.cw.Write([]string{r.strategy.Convert(fmt.Sprint(v))})
 	}
 	return nil
 }
@@ -0,0 +1,169 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// HTMLRenderer renders values as HTML: structs and maps as description
+// lists, slices of structs as tables with the columns of their table tags,
+// and other slices as lists. Hebrew cells are marked dir="rtl", and the RTL
+// text of other cells is wrapped in a <span dir="rtl">.
+type HTMLRenderer struct {
+	w io.Writer
+
+	// page is whether the output is a standalone page, rather than a
+	// fragment to paste into one.
+	page    bool
+	started bool
+}
+
+// NewHTMLRenderer returns a renderer that writes values as HTML, as a
+// standalone page when page is set.
+func NewHTMLRenderer(w io.Writer, page bool) *HTMLRenderer {
+	return &HTMLRenderer{w: w, page: page}
+}
+
+// pageHeader and pageFooter surround the values of a standalone page.
+const (
+	pageHeader = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Sefaria</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; margin: 2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: .25em .5em; text-align: start; vertical-align: top; }
+dt { font-weight: bold; }
+</style>
+</head>
+<body>
+`
+	pageFooter = `</body>
+</html>
+`
+)
+
+func (r *HTMLRenderer) Render(v any) error {
+	r.start()
+	r.renderValue(reflect.ValueOf(v), "p")
+	return nil
+}
+
+// Flush ends the page, if the output is one.
+func (r *HTMLRenderer) Flush() error {
+	if !r.page {
+		return nil
+	}
+	r.start()
+	_, err := io.WriteString(r.w, pageFooter)
+	return err
+}
+
+// start begins the page before the first value, if the output is one.
+func (r *HTMLRenderer) start() {
+	if r.page && !r.started {
+		io.WriteString(r.w, pageHeader)
+	}
+	r.started = true
+}
+
+// renderValue writes v. A value that holds no others is written in an
+// element named tag, such as the <dd> of a field.
+func (r *HTMLRenderer) renderValue(v reflect.Value, tag string) {
+	v, ok := indirect(v)
+	if !ok {
+		return
+	}
+	if !isNested(v) {
+		dir, text := r.text(v.Interface())
+		fmt.Fprintf(r.w, "<%s%s>%s</%s>\n", tag, dir, text, tag)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		fmt.Fprintln(r.w, "<dl>")
+		for _, f := range entries(v) {
+			if isEmpty(f.Value) {
+				continue
+			}
+			dir, name := r.text(f.Name)
+			fmt.Fprintf(r.w, "<dt%s>%s</dt>\n", dir, name)
+			if isNested(f.Value) {
+				fmt.Fprintln(r.w, "<dd>")
+				r.renderValue(f.Value, "p")
+				fmt.Fprintln(r.w, "</dd>")
+			} else {
+				r.renderValue(f.Value, "dd")
+			}
+		}
+		fmt.Fprintln(r.w, "</dl>")
+
+	case reflect.Slice, reflect.Array:
+		if t, ok := tableType(v); ok && v.Len() > 0 {
+			r.renderTable(v, usedColumns(v, tableColumns(t)))
+			return
+		}
+		fmt.Fprintln(r.w, "<ul>")
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if isNested(elem) {
+				fmt.Fprintln(r.w, "<li>")
+				r.renderValue(elem, "p")
+				fmt.Fprintln(r.w, "</li>")
+			} else {
+				r.renderValue(elem, "li")
+			}
+		}
+		fmt.Fprintln(r.w, "</ul>")
+	}
+}
+
+// renderTable writes the structs of slice as a table.
+func (r *HTMLRenderer) renderTable(slice reflect.Value, columns []column) {
+	fmt.Fprintln(r.w, "<table>")
+	fmt.Fprintln(r.w, "<thead>")
+	fmt.Fprint(r.w, "<tr>")
+	for _, c := range columns {
+		dir, text := r.text(c.Header)
+		fmt.Fprintf(r.w, `<th scope="col"%s>%s</th>`, dir, text)
+	}
+	fmt.Fprintln(r.w, "</tr>")
+	fmt.Fprintln(r.w, "</thead>")
+
+	fmt.Fprintln(r.w, "<tbody>")
+	for i := 0; i < slice.Len(); i++ {
+		fmt.Fprint(r.w, "<tr>")
+		for _, c := range columns {
+			var dir, text string
+			if v := c.cell(slice.Index(i)); v != nil {
+				dir, text = r.text(v)
+			}
+			fmt.Fprintf(r.w, "<td%s>%s</td>", dir, text)
+		}
+		fmt.Fprintln(r.w, "</tr>")
+	}
+	fmt.Fprintln(r.w, "</tbody>")
+	fmt.Fprintln(r.w, "</table>")
+}
+
+// text returns v escaped for HTML, with the dir attribute of the element it
+// is written in. Text that starts in Hebrew is marked dir="rtl" as a whole;
+// the RTL text within other text is wrapped in a <span dir="rtl">. Line
+// breaks are kept as <br>.
+func (r *HTMLRenderer) text(v any) (dir, text string) {
+	text = html.EscapeString(strings.TrimSpace(fmt.Sprint(v)))
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "<br>")
+	if plain := bidi.Raw.Convert(text); bidi.Direction(plain) == bidi.RightToLeft {
+		return ` dir="rtl"`, plain
+	}
+	return "", bidi.HTML.Apply(text)
+}
@@ -3,33 +3,41 @@ package render
 import (
 	"encoding/json"
 	"io"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
 )
 
 type JSONLineRenderer struct {
-	w   io.Writer
-	enc *json.Encoder
+	w        io.Writer
+	strategy bidi.Strategy
 }
 
-func NewJSONLineRenderer(w io.Writer) *JSONLineRenderer {
+func NewJSONLineRenderer(w io.Writer, strategy bidi.Strategy) *JSONLineRenderer {
 	return &JSONLineRenderer{
-		w:   w,
-		enc: json.NewEncoder(w),
+		w:        w,
+		strategy: strategy,
 	}
 }
 
 func (r *JSONLineRenderer) Render(v any) error {
-	return r.enc.Encode(v) // each object on a new line
+	data, err := bidi.MarshalJSON(v, r.strategy)
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(data, '\n')) // each object on a new line
+	return err
 }
 
 func (r *JSONLineRenderer) Flush() error { return nil }
 
 type JSONRenderer struct {
-	w      io.Writer
-	pretty bool
+	w        io.Writer
+	pretty   bool
+	strategy bidi.Strategy
 }
 
-func NewJSONRenderer(w io.Writer, pretty bool) *JSONRenderer {
-	return &JSONRenderer{w: w, pretty: pretty}
+func NewJSONRenderer(w io.Writer, pretty bool, strategy bidi.Strategy) *JSONRenderer {
+	return &JSONRenderer{w: w, pretty: pretty, strategy: strategy}
 }
 
 func (r *JSONRenderer) Render(v any) error {
@@ -43,6 +51,10 @@ func (r *JSONRenderer) Render(v any) error {
 	if err != nil {
 		return err
 	}
+	data, err = r.strategy.ConvertJSON(data)
+	if err != nil {
+		return err
+	}
 	_, err = r.w.Write(data)
 	if err != nil {
 		return err
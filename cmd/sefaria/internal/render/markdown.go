@@ -0,0 +1,146 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// MarkdownRenderer renders values as Markdown, for pasting into documents
+// and messages. The fields of a struct are listed under it, with a heading
+// for each field that holds more values; slices of structs are tables with
+// the columns of their table tags; and other slices are lists.
+type MarkdownRenderer struct {
+	w        io.Writer
+	strategy bidi.Strategy
+
+	// written is whether a block has been written, which the next one is
+	// set apart from by a blank line.
+	written bool
+}
+
+func NewMarkdownRenderer(w io.Writer, strategy bidi.Strategy) *MarkdownRenderer {
+	return &MarkdownRenderer{w: w, strategy: strategy}
+}
+
+func (r *MarkdownRenderer) Render(v any) error {
+	r.renderValue(reflect.ValueOf(v), 1)
+	return nil
+}
+
+func (r *MarkdownRenderer) Flush() error { return nil }
+
+// renderValue writes v with the headings of the values in it at level.
+func (r *MarkdownRenderer) renderValue(v reflect.Value, level int) {
+	v, ok := indirect(v)
+	if !ok {
+		return
+	}
+	if !isNested(v) {
+		r.block(r.text(v.Interface()))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		// Fields with a single value are listed before the sections of those
+		// that hold more, so that the list is not read as part of the last
+		// section.
+		var items []string
+		var nested []field
+		for _, f := range entries(v) {
+			switch {
+			case isEmpty(f.Value):
+			case isNested(f.Value):
+				nested = append(nested, f)
+			default:
+				items = append(items, fmt.Sprintf("- **%s:** %s", r.text(f.Name), r.text(f.Value.Interface())))
+			}
+		}
+		if len(items) > 0 {
+			r.block(strings.Join(items, "\n"))
+		}
+		for _, f := range nested {
+			r.block(strings.Repeat("#", min(level, 6)) + " " + r.text(f.Name))
+			r.renderValue(f.Value, level+1)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if t, ok := tableType(v); ok && v.Len() > 0 {
+			r.renderTable(v, usedColumns(v, tableColumns(t)))
+			return
+		}
+		var items []string
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if isNested(elem) {
+				if len(items) > 0 {
+					r.block(strings.Join(items, "\n"))
+					items = nil
+				}
+				r.renderValue(elem, level)
+				continue
+			}
+			items = append(items, "- "+r.text(elem.Interface()))
+		}
+		if len(items) > 0 {
+			r.block(strings.Join(items, "\n"))
+		}
+	}
+}
+
+// renderTable writes the structs of slice as a pipe table.
+func (r *MarkdownRenderer) renderTable(slice reflect.Value, columns []column) {
+	var b strings.Builder
+	row := func(cells []string) {
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		cells[i] = r.cell(c.Header)
+	}
+	row(cells)
+	for i := range cells {
+		cells[i] = "---"
+	}
+	row(cells)
+	for i := 0; i < slice.Len(); i++ {
+		for j, c := range columns {
+			if v := c.cell(slice.Index(i)); v != nil {
+				cells[j] = r.cell(v)
+			} else {
+				cells[j] = ""
+			}
+		}
+		row(cells)
+	}
+	r.block(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// block writes a block of Markdown, set apart from the one before it.
+func (r *MarkdownRenderer) block(s string) {
+	if r.written {
+		fmt.Fprintln(r.w)
+	}
+	fmt.Fprintln(r.w, s)
+	r.written = true
+}
+
+// text returns v as it is written in a paragraph or list item, with its RTL
+// text marked with the renderer's strategy. Line breaks are kept as breaks
+// within the paragraph.
+func (r *MarkdownRenderer) text(v any) string {
+	s := strings.TrimSpace(fmt.Sprint(v))
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return r.strategy.Apply(strings.ReplaceAll(s, "\n", "<br>"))
+}
+
+// cell returns v as it is written in a table cell, where a pipe would end
+// the cell.
+func (r *MarkdownRenderer) cell(v any) string {
+	return strings.ReplaceAll(r.text(v), "|", `\|`)
+}
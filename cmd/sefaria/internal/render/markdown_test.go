@@ -0,0 +1,50 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+type markdownRow struct {
+	Ref   string `table:"Ref"`
+	HeRef string `table:"Hebrew"`
+}
+
+// TestMarkdownRenderer_RTL checks that Markdown keeps Hebrew in logical
+// order, marked with the strategy, however the terminal lays out text.
+func TestMarkdownRenderer_RTL(t *testing.T) {
+	rows := []markdownRow{{Ref: "Genesis 1:1", HeRef: "בראשית א׳:א׳"}}
+
+	tests := []struct {
+		strategy bidi.Strategy
+		want     string
+	}{
+		{bidi.Marks, "\u200fבראשית א׳:א׳\u200e"},
+		{bidi.Isolates, "\u2068בראשית א׳:א׳\u2069"},
+		{bidi.HTML, `<span dir="rtl">בראשית א׳:א׳</span>`},
+		{bidi.Raw, "בראשית א׳:א׳"},
+	}
+	for _, tt := range tests {
+		for _, layout := range []bool{true, false} {
+			var b bytes.Buffer
+			r := NewRenderer("markdown", &b, Options{Width: 80, Bidi: layout, Strategy: tt.strategy})
+			if err := r.Render(rows); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("%s: rendered %q, want a table of one row", tt.strategy, b.String())
+			}
+			if want := "| Genesis 1:1 | " + tt.want + " |"; lines[2] != want {
+				t.Errorf("%s, bidi %v: row = %q, want %q", tt.strategy, layout, lines[2], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package render
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// The helpers here walk values the same way for each of the renderers that
+// lay values out for people to read: text, Markdown and HTML.
+
+// indirect follows pointers and interfaces to the value they hold. It
+// reports false for nil.
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+// field is an exported field of a struct with its value.
+type field struct {
+	Name  string
+	Value reflect.Value
+}
+
+// fields returns the exported fields of the struct v, in order.
+func fields(v reflect.Value) []field {
+	t := v.Type()
+	var out []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		out = append(out, field{Name: f.Name, Value: v.Field(i)})
+	}
+	return out
+}
+
+// column is a column of a table of structs.
+type column struct {
+	Header string
+	Index  []int
+}
+
+// tableColumns returns the columns of a table of structs of type t: the
+// exported fields with a table tag other than "-", headed by the tag. Structs
+// without table tags have no columns, and are not shown as tables. Fields
+// holding a struct of plain values, such as a BilingualString, are split into
+// a column for each of its fields.
+func tableColumns(t reflect.Type) []column {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("table")
+		if !f.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		columns = append(columns, flatten(column{Header: tag, Index: f.Index}, f.Type)...)
+	}
+	return columns
+}
+
+// flatten splits column c, holding values of type t, into a column for each
+// field of t when t is a struct of plain values. The columns are headed by
+// the header of c with the name of the field, as in "Title (Hebrew)".
+func flatten(c column, t reflect.Type) []column {
+	if !flattens(t) {
+		return []column{c}
+	}
+	var out []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("table")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name := f.Name
+		if tag != "" {
+			name = tag
+		}
+		out = append(out, column{
+			Header: c.Header + " (" + name + ")",
+			Index:  append(slices.Clone(c.Index), i),
+		})
+	}
+	return out
+}
+
+// marshalerType is the type of values that marshal themselves.
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+
+// flattens reports whether t is a struct whose exported fields all hold
+// plain values. Structs that marshal themselves, such as dates, are shown
+// whole.
+func flattens(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return false
+	}
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Pointer,
+			reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+			return false
+		}
+		n++
+	}
+	return n > 0
+}
+
+// usedColumns returns the columns of the table of the structs of slice that
+// are set in at least one of them, so that the table leaves out the ones
+// that would be blank. A table with nothing set keeps all of its columns.
+func usedColumns(slice reflect.Value, columns []column) []column {
+	var out []column
+	for _, c := range columns {
+		for i := 0; i < slice.Len(); i++ {
+			if v := c.cell(slice.Index(i)); v != nil && !isEmpty(reflect.ValueOf(v)) {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return columns
+	}
+	return out
+}
+
+// cell returns the value of column c of row, which is nil when row is a nil
+// pointer.
+func (c column) cell(row reflect.Value) any {
+	row, ok := indirect(row)
+	if !ok {
+		return nil
+	}
+	return row.FieldByIndex(c.Index).Interface()
+}
+
+// tableType returns the type of the structs a slice holds, and reports
+// whether it holds structs, or pointers to them, to be shown as a table.
+func tableType(v reflect.Value) (reflect.Type, bool) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	t := v.Type().Elem()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct && len(tableColumns(t)) > 0
+}
+
+// isNested reports whether v holds other values that are laid out below it,
+// rather than beside it. Structs that print themselves, such as a
+// BilingualString or a date, are laid out as they print.
+func isNested(v reflect.Value) bool {
+	if e, ok := indirect(v); ok && e.Kind() == reflect.Struct && e.CanInterface() {
+		if _, ok := e.Interface().(fmt.Stringer); ok {
+			return false
+		}
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Pointer, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// isBlank reports whether v is an empty string, which is left out rather
+// than shown with no value.
+func isBlank(v reflect.Value) bool {
+	return v.Kind() == reflect.String && v.Len() == 0
+}
+
+// isEmpty reports whether v holds nothing to show: an empty string, a nil
+// pointer, an empty slice or map or a struct with none of its fields set.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct:
+		return v.IsZero()
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return isBlank(v)
+}
+
+// mapKeys returns the keys of the map v in order, so that maps are laid out
+// the same way each time.
+func mapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+	return keys
+}
+
+// entries returns the fields of the struct v, or the entries of the map v
+// named by their keys, for renderers that lay out both the same way.
+func entries(v reflect.Value) []field {
+	if v.Kind() == reflect.Struct {
+		return fields(v)
+	}
+	var out []field
+	for _, key := range mapKeys(v) {
+		out = append(out, field{Name: fmt.Sprint(key.Interface()), Value: v.MapIndex(key)})
+	}
+	return out
+}
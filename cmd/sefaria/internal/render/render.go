@@ -3,6 +3,10 @@ package render
 import (
 	"io"
 	"strings"
+	"text/template"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/query"
 )
 
 type Renderer interface {
@@ -10,23 +14,139 @@ type Renderer interface {
 	Flush() error
 }
 
-func NewRenderer(format string, w io.Writer) Renderer {
+// Options controls how renderers lay out their output.
+type Options struct {
+	// Width is the number of columns the text renderer wraps values at. They
+	// are not wrapped when it is 0.
+	Width int
+
+	// Truncate cuts the cells of text tables short to fit the width, rather
+	// than wrapping them.
+	Truncate bool
+
+	// Bidi lays out RTL text in visual order, for terminals that show
+	// characters in the order they are given.
+	Bidi bool
+
+	// Strategy is how RTL text is marked in JSON, CSV and Markdown output.
+	// JSON and CSV are only laid out in visual order with the default,
+	// bidi.Marks, as the other strategies leave the layout to whatever reads
+	// the output. Markdown is never reordered.
+	Strategy bidi.Strategy
+
+	// Template is what the template format renders values with.
+	Template *template.Template
+
+	// Query filters and projects each value before it is rendered. Values
+	// are rendered as they are when it is nil.
+	Query *query.Query
+}
+
+func NewRenderer(format string, w io.Writer, opts Options) Renderer {
+	r := newRenderer(format, w, opts)
+	if opts.Query == nil {
+		return r
+	}
+	return &queryRenderer{Renderer: r, query: opts.Query}
+}
+
+func newRenderer(format string, w io.Writer, opts Options) Renderer {
+	var out *bidi.Writer
+	if opts.Bidi && opts.Strategy == bidi.Marks {
+		out = bidi.NewWriter(w, true)
+	}
+
+	var r Renderer
 	switch strings.ToLower(format) {
 	case "json", "jsonl", "json-lines", "json-compact":
-		return NewJSONLineRenderer(w)
+		r = NewJSONLineRenderer(writer(w, out), opts.Strategy)
 	case "json-pretty":
-		return NewJSONRenderer(w, true)
+		r = NewJSONRenderer(writer(w, out), true, opts.Strategy)
 	case "yaml", "yml":
-		return NewYAMLRenderer(w)
+		r = NewYAMLRenderer(writer(w, out))
 	case "xml":
-		return NewXMLRenderer(w)
+		r = NewXMLRenderer(writer(w, out))
 	case "csv":
-		return NewCSVRenderer(w)
-	case "text", "pretty", "human":
-		return NewTextRenderer(w)
+		r = NewCSVRenderer(writer(w, out), opts.Strategy)
+	case "template", "tmpl":
+		r = NewTemplateRenderer(writer(w, out), opts.Template, opts.Strategy)
 	case "plain", "shell":
-		return NewLineRenderer(w)
+		r = NewLineRenderer(writer(w, out))
+	case "bilingual":
+		return NewBilingualRenderer(w, opts)
+	case "markdown", "md":
+		// Markdown is laid out by whatever shows it, so its RTL text is
+		// marked with the strategy rather than reordered for a terminal.
+		return NewMarkdownRenderer(w, opts.Strategy)
+	case "html", "html-page":
+		// HTML marks the direction of its own text, and is not laid out
+		// for a terminal.
+		return NewHTMLRenderer(w, strings.ToLower(format) == "html-page")
 	default:
-		return NewTextRenderer(w)
+		// The text and bilingual renderers lay out their own output, as
+		// they have to wrap lines before they are reordered.
+		return NewTextRenderer(w, opts)
+	}
+
+	if out == nil {
+		return r
+	}
+	return &bidiRenderer{Renderer: r, out: out}
+}
+
+// writer returns out when it is set, and w otherwise.
+func writer(w io.Writer, out *bidi.Writer) io.Writer {
+	if out == nil {
+		return w
+	}
+	return out
+}
+
+// bidiRenderer lays out the output of a renderer in visual order.
+type bidiRenderer struct {
+	Renderer
+	out *bidi.Writer
+}
+
+// Flush flushes the renderer, then writes out any text held back until it
+// had a whole line.
+func (r *bidiRenderer) Flush() error {
+	if err := r.Renderer.Flush(); err != nil {
+		return err
+	}
+	return r.out.Flush()
+}
+
+// queryRenderer filters and projects values before a renderer renders them.
+type queryRenderer struct {
+	Renderer
+	query *query.Query
+
+	// err is the first value that could not be queried, kept for Flush as
+	// the result of Render is often not checked.
+	err error
+}
+
+func (r *queryRenderer) Render(v any) error {
+	v, err := r.query.Apply(v)
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		return err
+	}
+	if v == nil {
+		// The value was filtered out.
+		return nil
+	}
+	return r.Renderer.Render(v)
+}
+
+// Flush flushes the renderer, then returns the first error from querying a
+// value.
+func (r *queryRenderer) Flush() error {
+	if err := r.Renderer.Flush(); err != nil {
+		return err
 	}
+	return r.err
 }
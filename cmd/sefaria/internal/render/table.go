@@ -0,0 +1,211 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// minColumnWidth is the narrowest a column is made to fit a table to the
+// terminal, unless everything in it is narrower still.
+const minColumnWidth = 6
+
+// tableStyle is how a table separates its columns and its header, and how it
+// styles them. Tables on a terminal are drawn with borders; tables written to
+// a file or a pipe are separated with spaces and dashes alone, so that they
+// hold nothing but text.
+type tableStyle struct {
+	gap   string
+	rule  string
+	cross string
+
+	header func(string) string
+	border func(string) string
+}
+
+// newTableStyle returns the style of tables written to w. Colors are left
+// out when w is not a terminal, or NO_COLOR is set.
+func newTableStyle(w io.Writer) tableStyle {
+	if !IsTerminal(w) {
+		return tableStyle{gap: "  ", rule: "-", cross: "  ", header: plainText, border: plainText}
+	}
+	colors := lipgloss.NewRenderer(w)
+	return tableStyle{
+		gap:    " │ ",
+		rule:   "─",
+		cross:  "─┼─",
+		header: styled(colors.NewStyle().Bold(true)),
+		border: styled(colors.NewStyle().Faint(true)),
+	}
+}
+
+// styled returns a function that renders text with style.
+func styled(style lipgloss.Style) func(string) string {
+	return func(s string) string { return style.Render(s) }
+}
+
+// plainText leaves text as it is, for tables without colors.
+func plainText(s string) string {
+	return s
+}
+
+// table lays out rows of cells in columns padded with spaces.
+type table struct {
+	headers []string
+	rows    [][]string
+
+	style  tableStyle
+	layout bidi.Layout
+
+	// truncate cuts cells that do not fit their column short, rather than
+	// wrapping them.
+	truncate bool
+}
+
+// write writes the table with each line indented by indent. When the
+// layout has a width, the columns are narrowed to fit the table within it.
+func (t *table) write(w io.Writer, indent string) {
+	natural := make([]int, len(t.headers))
+	floors := make([]int, len(t.headers))
+	for i, header := range t.headers {
+		natural[i] = bidi.StringWidth(header)
+		floors[i] = natural[i]
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			for _, line := range strings.Split(cell, "\n") {
+				natural[i] = max(natural[i], bidi.StringWidth(line))
+			}
+			for _, word := range strings.Fields(cell) {
+				floors[i] = max(floors[i], bidi.StringWidth(word))
+			}
+		}
+	}
+
+	widths := natural
+	if t.layout.Width > 0 {
+		gaps := bidi.StringWidth(t.style.gap) * (len(t.headers) - 1)
+		widths = fit(natural, floors, t.layout.Width-bidi.StringWidth(indent)-gaps)
+	}
+
+	t.writeRow(w, indent, t.headers, widths, t.style.header)
+
+	rules := make([]string, len(widths))
+	for i, width := range widths {
+		rules[i] = strings.Repeat(t.style.rule, width)
+	}
+	fmt.Fprintln(w, indent+t.style.border(strings.Join(rules, t.style.cross)))
+
+	for _, row := range t.rows {
+		t.writeRow(w, indent, row, widths, plainText)
+	}
+}
+
+// writeRow writes the cells of a row, each laid out on as many lines as it
+// takes within the width of its column.
+func (t *table) writeRow(w io.Writer, indent string, cells []string, widths []int, style func(string) string) {
+	lines := make([][]string, len(cells))
+	height := 1
+	for i, cell := range cells {
+		lines[i] = t.cellLines(cell, widths[i])
+		height = max(height, len(lines[i]))
+	}
+
+	gap := t.style.border(t.style.gap)
+	for n := 0; n < height; n++ {
+		var b strings.Builder
+		b.WriteString(indent)
+		for i := range cells {
+			var line string
+			if n < len(lines[i]) {
+				line = lines[i][n]
+			}
+			if i > 0 {
+				b.WriteString(gap)
+			}
+			if i < len(cells)-1 {
+				line += strings.Repeat(" ", max(0, widths[i]-bidi.StringWidth(line)))
+			}
+			b.WriteString(style(line))
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+}
+
+// cellLines lays out cell within width columns, wrapping it or cutting it
+// short. RTL cells are aligned on the right when they are laid out in visual
+// order.
+func (t *table) cellLines(cell string, width int) []string {
+	if cell == "" {
+		return nil
+	}
+	layout := t.layout
+	layout.Width = width
+	if t.truncate {
+		cell = truncate(strings.Join(strings.Fields(cell), " "), width)
+	}
+	return layout.Lines(cell)
+}
+
+// truncate cuts s short to fit within width columns, ending it with an
+// ellipsis when it does not.
+func truncate(s string, width int) string {
+	if bidi.StringWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := bidi.StringWidth(string(r))
+		if used+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	return strings.TrimRight(b.String(), " ") + "…"
+}
+
+// fit returns the widths of columns as wide as natural, narrowed to fit
+// within avail columns. The widest columns are narrowed first, and none is
+// made narrower than its floor, the width of its header or its longest word,
+// so that cells wrap between words. When the floors do not fit either, the
+// widest columns are narrowed further, down to minColumnWidth. The table is
+// wider than avail when even that does not fit.
+func fit(natural, floors []int, avail int) []int {
+	widths := slices.Clone(natural)
+	narrow(widths, floors, avail)
+
+	least := make([]int, len(natural))
+	for i, n := range natural {
+		least[i] = min(n, minColumnWidth)
+	}
+	narrow(widths, least, avail)
+	return widths
+}
+
+// narrow takes a column at a time from the widest of widths until they fit
+// within avail columns, or each is as narrow as its floor.
+func narrow(widths, floors []int, avail int) {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	for total > avail {
+		widest := -1
+		for i, w := range widths {
+			if w > floors[i] && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
@@ -0,0 +1,269 @@
+package render
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// versionRow is a row of a table of versions, as a command lists them.
+type versionRow struct {
+	Title    string `table:"Title"`
+	Language string `table:"Language"`
+	License  string `table:"License"`
+	Source   string `table:"Source"`
+	Notes    string
+}
+
+var versionRows = []versionRow{
+	{"The Koren Jerusalem Bible", "en", "CC-BY-NC", "Koren Publishers Jerusalem", "not a column"},
+	{"Tanach with Ta'amei Hamikra", "he", "Public Domain", "https://tanach.us", ""},
+}
+
+// writeTable writes a table of rows as the text renderer lays it out, with
+// the style of tables written to a pipe.
+func writeTable(t *testing.T, tbl *table) []string {
+	t.Helper()
+	var b bytes.Buffer
+	tbl.style = newTableStyle(&b)
+	tbl.write(&b, "")
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
+
+// tableCells splits the lines of a piped table into their cells, at the
+// columns where its rule of dashes starts each one.
+func tableCells(t *testing.T, lines []string) [][]string {
+	t.Helper()
+	if len(lines) < 2 {
+		t.Fatalf("table has no rule: %q", lines)
+	}
+	var starts []int
+	for i, r := range lines[1] {
+		if r == '-' && (i == 0 || lines[1][i-1] == ' ') {
+			starts = append(starts, i)
+		}
+	}
+
+	var out [][]string
+	for _, line := range lines {
+		cells := make([]string, len(starts))
+		col, n := 0, 0
+		for _, r := range line {
+			for n+1 < len(starts) && col >= starts[n+1] {
+				n++
+			}
+			if n+1 < len(starts) && col+bidi.StringWidth(string(r)) > starts[n+1] {
+				t.Fatalf("%q crosses into column %d at %d", line, n+1, starts[n+1])
+			}
+			cells[n] += string(r)
+			col += bidi.StringWidth(string(r))
+		}
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+		out = append(out, cells)
+	}
+	return out
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		natural []int
+		floors  []int
+		avail   int
+		want    []int
+	}{
+		{"fits", []int{5, 8, 8}, []int{5, 8, 8}, 30, []int{5, 8, 8}},
+		{"widest first", []int{30, 8, 20}, []int{9, 8, 6}, 40, []int{16, 8, 16}},
+		{"keeps floors", []int{30, 8, 20}, []int{9, 8, 12}, 30, []int{10, 8, 12}},
+		{"below floors", []int{30, 8, 20}, []int{28, 8, 20}, 30, []int{11, 8, 11}},
+		{"narrow columns", []int{3, 30}, []int{3, 10}, 5, []int{3, 6}},
+		{"too narrow", []int{30, 8, 20}, []int{9, 8, 6}, 10, []int{6, 6, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			natural := slices.Clone(tt.natural)
+			if got := fit(natural, tt.floors, tt.avail); !slices.Equal(got, tt.want) {
+				t.Errorf("fit(%v, %v, %d) = %v, want %v", tt.natural, tt.floors, tt.avail, got, tt.want)
+			}
+			if !slices.Equal(natural, tt.natural) {
+				t.Errorf("fit() changed natural to %v", natural)
+			}
+		})
+	}
+}
+
+func TestTextRenderer_TablePiped(t *testing.T) {
+	var b bytes.Buffer
+	r := NewTextRenderer(&b, Options{Width: 100})
+	if err := r.Render(struct{ Versions []versionRow }{versionRows}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Versions:
+  Title                        Language  License        Source
+  ---------------------------  --------  -------------  --------------------------
+  The Koren Jerusalem Bible    en        CC-BY-NC       Koren Publishers Jerusalem
+  Tanach with Ta'amei Hamikra  he        Public Domain  https://tanach.us
+`
+	if got := b.String(); got != want {
+		t.Errorf("rendered\n%s\nwant\n%s", got, want)
+	}
+	for _, border := range []string{"\x1b", "│", "─", "┼"} {
+		if strings.Contains(b.String(), border) {
+			t.Errorf("piped table holds %q", border)
+		}
+	}
+}
+
+func TestTextRenderer_TableUntagged(t *testing.T) {
+	type untagged struct{ Name, Slug string }
+
+	var b bytes.Buffer
+	r := NewTextRenderer(&b, Options{})
+	if err := r.Render(struct{ Items []untagged }{[]untagged{{"Genesis", "genesis"}}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "Items:\n  - \n    Name: Genesis\n    Slug: genesis\n"
+	if got := b.String(); got != want {
+		t.Errorf("rendered %q, want structs without table tags listed: %q", got, want)
+	}
+}
+
+func TestTable_DisplayWidth(t *testing.T) {
+	tbl := &table{
+		headers: []string{"Title", "Language", "Count"},
+		rows: [][]string{
+			{"聖經", "zh", "1"},
+			{"בראשית", "he", "50"},
+			{"Genesis", "en", "50"},
+		},
+	}
+
+	tbl.layout = bidi.Layout{Logical: true}
+	want := []string{
+		"Title    Language  Count",
+		"-------  --------  -----",
+		"聖經     zh        1",
+		"בראשית   he        50",
+		"Genesis  en        50",
+	}
+	if got := writeTable(t, tbl); !slices.Equal(got, want) {
+		t.Errorf("logical order table =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// In visual order, RTL cells are aligned on the right of their column.
+	tbl.layout = bidi.Layout{}
+	want[3] = " תישארב  he        50"
+	if got := writeTable(t, tbl); !slices.Equal(got, want) {
+		t.Errorf("visual order table =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTable_Wrap(t *testing.T) {
+	const width = 60
+
+	tbl := &table{headers: []string{"Title", "Language", "License", "Source"}}
+	for _, v := range versionRows {
+		tbl.rows = append(tbl.rows, []string{v.Title, v.Language, v.License, v.Source})
+	}
+	tbl.layout = bidi.Layout{Width: width, Logical: true}
+	lines := writeTable(t, tbl)
+
+	for _, line := range lines {
+		if w := bidi.StringWidth(line); w > width {
+			t.Errorf("line %q is %d columns wide, want at most %d", line, w, width)
+		}
+	}
+	if len(lines) <= 2+len(tbl.rows) {
+		t.Fatalf("table of %d lines does not wrap:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+
+	// Each cell wraps between its words, so its lines join back into it.
+	cells := tableCells(t, lines)
+	if !slices.Equal(cells[0], tbl.headers) {
+		t.Errorf("header = %q, want %q", cells[0], tbl.headers)
+	}
+	// Each row starts on the line with its language, which never wraps.
+	var rows [][]string
+	for _, line := range cells[2:] {
+		if line[1] != "" || len(rows) == 0 {
+			rows = append(rows, make([]string, len(line)))
+		}
+		row := rows[len(rows)-1]
+		for i, cell := range line {
+			if cell != "" {
+				row[i] = strings.TrimSpace(row[i] + " " + cell)
+			}
+		}
+	}
+	if len(rows) != len(tbl.rows) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(rows), len(tbl.rows), strings.Join(lines, "\n"))
+	}
+	for i, row := range rows {
+		if !slices.Equal(row, tbl.rows[i]) {
+			t.Errorf("row %d = %q, want %q", i, row, tbl.rows[i])
+		}
+	}
+}
+
+func TestTable_Truncate(t *testing.T) {
+	const width = 40
+
+	tbl := &table{headers: []string{"Title", "Language", "License", "Source"}, truncate: true}
+	for _, v := range versionRows {
+		tbl.rows = append(tbl.rows, []string{v.Title, v.Language, v.License, v.Source})
+	}
+	tbl.layout = bidi.Layout{Width: width, Logical: true}
+	lines := writeTable(t, tbl)
+
+	if len(lines) != 2+len(tbl.rows) {
+		t.Fatalf("truncated table has %d lines, want a line for each row:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	for _, line := range lines {
+		if w := bidi.StringWidth(line); w > width {
+			t.Errorf("line %q is %d columns wide, want at most %d", line, w, width)
+		}
+	}
+	cells := tableCells(t, lines)
+	for i, row := range cells[2:] {
+		for j, cell := range row {
+			full := tbl.rows[i][j]
+			if cell != full && (!strings.HasSuffix(cell, "…") || !strings.HasPrefix(full, strings.TrimSuffix(cell, "…"))) {
+				t.Errorf("cell %q of %q, want it whole or cut short with an ellipsis", cell, full)
+			}
+		}
+	}
+	if cells[2][0] == tbl.rows[0][0] {
+		t.Errorf("title %q was not cut short", cells[2][0])
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"Genesis", 10, "Genesis"},
+		{"Genesis", 7, "Genesis"},
+		{"The Koren Jerusalem Bible", 10, "The Koren…"},
+		{"The Koren Jerusalem Bible", 11, "The Koren…"},
+		{"聖經聖經", 5, "聖經…"},
+		{"聖經聖經", 6, "聖經…"},
+		{"בראשית ברא", 6, "בראשי…"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := bidi.StringWidth(got); w > tt.width {
+			t.Errorf("truncate(%q, %d) is %d columns wide", tt.s, tt.width, w)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/bidi"
+	"github.com/ryanfaerman/go-sefaria/types"
+)
+
+// ErrNoTemplate is returned when the template format is used without a
+// template.
+var ErrNoTemplate = errors.New("no template given")
+
+// TemplateRenderer renders each value with a Go template, for output shaped
+// to fit a script or a notification.
+type TemplateRenderer struct {
+	w        io.Writer
+	tmpl     *template.Template
+	strategy bidi.Strategy
+
+	// err is the first error from executing the template, kept for Flush as
+	// the result of Render is often not checked.
+	err error
+}
+
+func NewTemplateRenderer(w io.Writer, tmpl *template.Template, strategy bidi.Strategy) *TemplateRenderer {
+	return &TemplateRenderer{w: w, tmpl: tmpl, strategy: strategy}
+}
+
+// Render executes the template with v as its data. RTL text in the output is
+// marked with the renderer's strategy.
+func (r *TemplateRenderer) Render(v any) error {
+	err := r.render(v)
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return err
+}
+
+func (r *TemplateRenderer) render(v any) error {
+	if r.tmpl == nil {
+		return ErrNoTemplate
+	}
+
+	var b bytes.Buffer
+	if err := r.tmpl.Execute(&b, v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(r.w, r.strategy.Convert(b.String()))
+	return err
+}
+
+// Flush returns the first error from rendering a value.
+func (r *TemplateRenderer) Flush() error { return r.err }
+
+// ParseTemplate parses text as a template for the TemplateRenderer, with the
+// functions of TemplateFuncs available to it.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs).Parse(text)
+}
+
+// TemplateFuncs are the functions templates may use besides the builtin ones:
+//
+//	hebrew     the Hebrew numeral for a number: {{hebrew 5785}} is ה׳תשפ״ה
+//	bidi       marks the RTL text of a value, as --bidi-strategy asks
+//	dir        the direction of a string: ltr, rtl or auto
+//	date       formats a date with a Go layout or one of iso, rfc3339,
+//	           rfc1123 and kitchen: {{.Date | date "Jan 2, 2006"}}
+//	now        the current time: {{now | date "iso"}}
+//	url        the sefaria.org link to read a ref
+//	topicURL   the sefaria.org link to a topic, by its slug
+//	sheetURL   the sefaria.org link to a source sheet, by its id
+//	searchURL  the sefaria.org link to the results of a search
+//	join       joins a list of strings with a separator
+//	json       the value as JSON, with its RTL text left unmarked
+var TemplateFuncs = template.FuncMap{
+	"hebrew": hebrewNumeral,
+	"bidi":   markRTL,
+	"dir": func(v any) string {
+		return bidi.Direction(plain(v)).String()
+	},
+	"date": formatDate,
+	"now":  time.Now,
+	"url": func(ref any) string {
+		return sefaria.DefaultSite.Reader(plain(ref), nil).String()
+	},
+	"topicURL": func(slug string) string {
+		return sefaria.DefaultSite.Topic(slug, nil).String()
+	},
+	"sheetURL": func(id int) string {
+		return sefaria.DefaultSite.Sheet(id, nil).String()
+	},
+	"searchURL": func(query string) string {
+		return sefaria.DefaultSite.Search(query, nil).String()
+	},
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	"json": func(v any) (string, error) {
+		// Marks would be converted along with the rest of the output, which
+		// for HTML would break the quoting of strings.
+		data, err := bidi.MarshalJSON(v, bidi.Raw)
+		return string(data), err
+	},
+}
+
+// plain returns v as a string without directional marks.
+func plain(v any) string {
+	if s, ok := v.(bidi.String); ok {
+		return string(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// markRTL marks the RTL text of v with directional marks, which Render
+// then converts to the renderer's strategy.
+func markRTL(v any) string {
+	if s, ok := v.(bidi.String); ok {
+		return s.String()
+	}
+	return bidi.String(fmt.Sprint(v)).String()
+}
+
+// dateLayouts are the names date accepts in place of a layout.
+var dateLayouts = map[string]string{
+	"iso":     time.DateOnly,
+	"rfc3339": time.RFC3339,
+	"rfc1123": time.RFC1123,
+	"kitchen": time.Kitchen,
+}
+
+// formatDate formats a time, a date from Sefaria or a string holding a
+// date with layout.
+func formatDate(layout string, v any) (string, error) {
+	if l, ok := dateLayouts[strings.ToLower(layout)]; ok {
+		layout = l
+	}
+
+	var t time.Time
+	switch d := v.(type) {
+	case time.Time:
+		t = d
+	case *time.Time:
+		t = *d
+	case types.Date:
+		t = d.Time
+	case *types.Date:
+		t = d.Time
+	case string:
+		var err error
+		if t, err = dateparse.ParseAny(d); err != nil {
+			return "", fmt.Errorf("cannot parse date: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("cannot format %T as a date", v)
+	}
+	return t.Format(layout), nil
+}
+
+// hebrewNumerals are the letters that make up Hebrew numerals, from the
+// largest value to the smallest.
+var hebrewNumerals = []struct {
+	value  int
+	letter string
+}{
+	{400, "ת"}, {300, "ש"}, {200, "ר"}, {100, "ק"},
+	{90, "צ"}, {80, "פ"}, {70, "ע"}, {60, "ס"}, {50, "נ"},
+	{40, "מ"}, {30, "ל"}, {20, "כ"}, {10, "י"},
+	{9, "ט"}, {8, "ח"}, {7, "ז"}, {6, "ו"}, {5, "ה"},
+	{4, "ד"}, {3, "ג"}, {2, "ב"}, {1, "א"},
+}
+
+// hebrewNumeral returns n written as a Hebrew numeral, such as ט״ו for 15.
+// Thousands are written before the rest with a geresh, as in years such as
+// ה׳תשפ״ה. Numbers below 1 are written with digits.
+func hebrewNumeral(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+
+	var thousands string
+	if n >= 1000 {
+		thousands = strings.Join(hebrewLetters(n/1000), "") + "׳"
+		if n %= 1000; n == 0 {
+			return thousands
+		}
+	}
+
+	letters := hebrewLetters(n)
+	if len(letters) == 1 {
+		return thousands + letters[0] + "׳"
+	}
+	last := len(letters) - 1
+	return thousands + strings.Join(letters[:last], "") + "״" + letters[last]
+}
+
+// hebrewLetters returns the letters of the Hebrew numeral for n, from the
+// largest value to the smallest.
+func hebrewLetters(n int) []string {
+	var letters []string
+	for _, l := range hebrewNumerals {
+		// 15 and 16 are written as 9+6 and 9+7, so that they do not spell
+		// a name of God.
+		switch n {
+		case 15:
+			return append(letters, "ט", "ו")
+		case 16:
+			return append(letters, "ט", "ז")
+		}
+		for n >= l.value {
+			letters = append(letters, l.letter)
+			n -= l.value
+		}
+	}
+	return letters
+}
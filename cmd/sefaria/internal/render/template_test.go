@@ -0,0 +1,181 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/bidi"
+	"github.com/ryanfaerman/go-sefaria/types"
+)
+
+func TestHebrewNumeral(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "א׳"},
+		{9, "ט׳"},
+		{10, "י׳"},
+		{11, "י״א"},
+		{15, "ט״ו"},
+		{16, "ט״ז"},
+		{17, "י״ז"},
+		{20, "כ׳"},
+		{115, "קט״ו"},
+		{116, "קט״ז"},
+		{400, "ת׳"},
+		{500, "ת״ק"},
+		{613, "תרי״ג"},
+		{1000, "א׳"},
+		{5000, "ה׳"},
+		{5015, "ה׳ט״ו"},
+		{5785, "ה׳תשפ״ה"},
+		{0, "0"},
+		{-3, "-3"},
+	}
+	for _, tt := range tests {
+		if got := hebrewNumeral(tt.n); got != tt.want {
+			t.Errorf("hebrewNumeral(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	at := time.Date(2025, time.March, 14, 15, 4, 5, 0, time.UTC)
+	date := types.Date{Time: at}
+
+	tests := []struct {
+		name   string
+		layout string
+		v      any
+		want   string
+	}{
+		{"iso", "iso", at, "2025-03-14"},
+		{"rfc3339", "rfc3339", at, "2025-03-14T15:04:05Z"},
+		{"rfc1123", "rfc1123", at, "Fri, 14 Mar 2025 15:04:05 UTC"},
+		{"kitchen", "kitchen", at, "3:04PM"},
+		{"layout names ignore case", "ISO", at, "2025-03-14"},
+		{"go layout", "Jan 2, 2006", at, "Mar 14, 2025"},
+		{"time pointer", "iso", &at, "2025-03-14"},
+		{"date", "Monday", date, "Friday"},
+		{"date pointer", "iso", &date, "2025-03-14"},
+		{"string", "iso", "2025-03-14", "2025-03-14"},
+		{"string with time", "kitchen", "2025-03-14T15:04:05Z", "3:04PM"},
+		{"loose string", "2006-01-02", "March 14, 2025", "2025-03-14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatDate(tt.layout, tt.v)
+			if err != nil {
+				t.Fatalf("formatDate(%q, %v) error = %v", tt.layout, tt.v, err)
+			}
+			if got != tt.want {
+				t.Errorf("formatDate(%q, %v) = %q, want %q", tt.layout, tt.v, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := formatDate("iso", "not a date"); err == nil || !strings.HasPrefix(err.Error(), "cannot parse date") {
+		t.Errorf("formatDate of a bad string error = %v, want cannot parse date", err)
+	}
+	if _, err := formatDate("iso", 42); err == nil || err.Error() != "cannot format int as a date" {
+		t.Errorf("formatDate of an int error = %v, want cannot format int as a date", err)
+	}
+}
+
+// execute renders v with the template text as the template format does.
+func execute(t *testing.T, text string, v any) (string, error) {
+	t.Helper()
+	tmpl, err := ParseTemplate("test", text)
+	if err != nil {
+		t.Fatalf("ParseTemplate(%q) error = %v", text, err)
+	}
+	var b bytes.Buffer
+	err = NewTemplateRenderer(&b, tmpl, bidi.Raw).Render(v)
+	return b.String(), err
+}
+
+func TestTemplateRenderer(t *testing.T) {
+	learnings := []sefaria.ScheduledLearning{
+		{Title: sefaria.BilingualString{English: "Parashat Hashavua", Hebrew: "פרשת השבוע"}, Ref: "Genesis 1:1-6:8"},
+		{Title: sefaria.BilingualString{English: "Daf Yomi", Hebrew: "דף יומי"}, Ref: "Berakhot 2a"},
+	}
+
+	got, err := execute(t, "{{range .}}{{.Title.English}}: {{url .Ref}}{{end}}", learnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Parashat Hashavua: https://www.sefaria.org/Genesis.1.1-6.8" +
+		"Daf Yomi: https://www.sefaria.org/Berakhot.2a"
+	if got != want {
+		t.Errorf("rendered\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		text string
+		v    any
+		want string
+	}{
+		{`{{hebrew 5785}}`, nil, "ה׳תשפ״ה"},
+		{`{{hebrew .}}`, 15, "ט״ו"},
+		{`{{. | date "iso"}}`, types.Date{Time: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)}, "2025-03-14"},
+		{`{{dir .}}`, "בראשית", "rtl"},
+		{`{{dir .}}`, "Genesis", "ltr"},
+		{`{{dir .}}`, "1:1", "auto"},
+		{`{{url .}}`, "Rashi on Genesis 1:1:1", "https://www.sefaria.org/Rashi_on_Genesis.1.1.1"},
+		{`{{topicURL .}}`, "shabbat", "https://www.sefaria.org/topics/shabbat"},
+		{`{{sheetURL .}}`, 1234, "https://www.sefaria.org/sheets/1234"},
+		{`{{searchURL .}}`, "shabbat candles", "https://www.sefaria.org/search?q=shabbat+candles&tab=text"},
+		{`{{join ", " .}}`, []string{"Genesis", "Exodus"}, "Genesis, Exodus"},
+		{`{{json .}}`, sefaria.BilingualString{English: "Genesis", Hebrew: "בראשית"}, `{"en":"Genesis","he":"בראשית"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := execute(t, tt.text, tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("%s of %v = %q, want %q", tt.text, tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateRenderer_NoTemplate(t *testing.T) {
+	var b bytes.Buffer
+	r := NewRenderer("template", &b, Options{})
+	if err := r.Render("Genesis"); !errors.Is(err, ErrNoTemplate) {
+		t.Errorf("Render() error = %v, want ErrNoTemplate", err)
+	}
+	if err := r.Flush(); !errors.Is(err, ErrNoTemplate) {
+		t.Errorf("Flush() error = %v, want ErrNoTemplate", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("rendered %q without a template", b.String())
+	}
+}
+
+func TestTemplateRenderer_Error(t *testing.T) {
+	tmpl, err := ParseTemplate("test", "{{.Missing}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	r := NewTemplateRenderer(&b, tmpl, bidi.Raw)
+
+	first := r.Render(sefaria.ScheduledLearning{})
+	if first == nil {
+		t.Fatal("Render() error = nil for a field the value does not have")
+	}
+	r.Render(sefaria.ScheduledLearning{})
+	if err := r.Flush(); err != first {
+		t.Errorf("Flush() error = %v, want the first error %v", err, first)
+	}
+}
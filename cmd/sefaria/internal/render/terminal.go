@@ -0,0 +1,31 @@
+package render
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// IsTerminal reports whether w writes to a terminal, rather than to a file
+// or a pipe.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// TerminalWidth returns the number of columns of the terminal w writes to.
+// When w is not a terminal, it falls back to the COLUMNS environment
+// variable, and to 0 when that is not set either.
+func TerminalWidth(w io.Writer) int {
+	if IsTerminal(w) {
+		if width, _, err := term.GetSize(w.(*os.File).Fd()); err == nil && width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 0
+}
@@ -7,14 +7,31 @@ import (
 	"strings"
 
 	"github.com/mattn/go-runewidth"
+	"github.com/ryanfaerman/go-sefaria/bidi"
 )
 
+// minWidth is the fewest columns a value is wrapped at, however far it is
+// indented.
+const minWidth = 20
+
 type TextRenderer struct {
-	w io.Writer
+	w        io.Writer
+	layout   bidi.Layout
+	style    tableStyle
+	truncate bool
 }
 
-func NewTextRenderer(w io.Writer) *TextRenderer {
-	return &TextRenderer{w: w}
+// NewTextRenderer returns a renderer that writes values for people to read.
+// Values are wrapped at opts.Width, and laid out in visual order when
+// opts.Bidi is set. Tables are drawn with borders and colors on a terminal,
+// and with spaces alone otherwise.
+func NewTextRenderer(w io.Writer, opts Options) *TextRenderer {
+	return &TextRenderer{
+		w:        w,
+		layout:   bidi.Layout{Width: opts.Width, Logical: !opts.Bidi},
+		style:    newTableStyle(w),
+		truncate: opts.Truncate,
+	}
 }
 
 func (r *TextRenderer) Render(v any) error {
@@ -26,42 +43,25 @@ func (r *TextRenderer) Flush() error { return nil }
 func (r *TextRenderer) renderValue(v reflect.Value, level int) error {
 	indent := strings.Repeat("  ", level)
 
-	if !v.IsValid() {
+	v, ok := indirect(v)
+	if !ok {
 		fmt.Fprintf(r.w, "%s<nil>\n", indent)
 		return nil
 	}
 
-	// unwrap pointers and interfaces
-	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
-		if v.IsNil() {
-			fmt.Fprintf(r.w, "%s<nil>\n", indent)
-			return nil
-		}
-		v = v.Elem()
-	}
-
 	switch v.Kind() {
 	case reflect.Struct:
-		t := v.Type()
-		for i := 0; i < t.NumField(); i++ {
-			f := t.Field(i)
-			if f.PkgPath != "" { // unexported
-				continue
-			}
-			val := v.Field(i)
-			if val.Kind() == reflect.Slice && val.Len() > 0 && val.Index(0).Kind() == reflect.Struct {
-				// Render as table if slice of struct
+		for _, f := range fields(v) {
+			if _, ok := tableType(f.Value); ok && f.Value.Len() > 0 {
 				fmt.Fprintf(r.w, "%s%s:\n", indent, f.Name)
-				r.renderStructSliceTable(val, level+1)
+				r.renderTable(f.Value, level+1)
 				continue
 			}
-			if val.Kind() == reflect.Struct || val.Kind() == reflect.Slice || val.Kind() == reflect.Map || val.Kind() == reflect.Ptr {
+			if isNested(f.Value) {
 				fmt.Fprintf(r.w, "%s%s:\n", indent, f.Name)
-				r.renderValue(val, level+1)
-			} else if val.Kind() == reflect.String && val.String() == "" {
-				continue
-			} else {
-				fmt.Fprintf(r.w, "%s%s: %v\n", indent, f.Name, val.Interface())
+				r.renderValue(f.Value, level+1)
+			} else if !isBlank(f.Value) {
+				r.writeValue(indent+f.Name+": ", f.Value.Interface())
 			}
 		}
 
@@ -72,117 +72,70 @@ func (r *TextRenderer) renderValue(v reflect.Value, level int) error {
 				fmt.Fprintf(r.w, "%s- \n", indent)
 				r.renderValue(elem, level+1)
 			} else {
-				fmt.Fprintf(r.w, "%s- %v\n", indent, elem.Interface())
+				r.writeValue(indent+"- ", elem.Interface())
 			}
 		}
 
 	case reflect.Map:
-		for _, key := range v.MapKeys() {
+		for _, key := range mapKeys(v) {
 			val := v.MapIndex(key)
-			fmt.Fprintf(r.w, "%s%v: ", indent, key.Interface())
-			if val.Kind() == reflect.Struct || val.Kind() == reflect.Slice || val.Kind() == reflect.Map {
-				fmt.Fprintln(r.w)
+			prefix := indent + r.inline(key.Interface()) + ": "
+			if isNested(val) {
+				fmt.Fprintln(r.w, prefix)
 				r.renderValue(val, level+1)
 			} else {
-				fmt.Fprintf(r.w, "%v\n", val.Interface())
+				r.writeValue(prefix, val.Interface())
 			}
 		}
 
 	default:
-		fmt.Fprintf(r.w, "%s%v\n", indent, v.Interface())
+		r.writeValue(indent, v.Interface())
 	}
 
 	return nil
 }
 
-// renderStructSliceTable renders a slice of structs as an aligned table using table tags
-func (r *TextRenderer) renderStructSliceTable(slice reflect.Value, level int) {
-	if slice.Len() == 0 {
-		return
+// writeValue writes v after prefix, wrapping it to the width left after the
+// prefix. Lines after the first are indented to line up with the first.
+func (r *TextRenderer) writeValue(prefix string, v any) {
+	layout := r.layout
+	width := runewidth.StringWidth(prefix)
+	if layout.Width > 0 {
+		layout.Width = max(layout.Width-width, minWidth)
 	}
-	indent := strings.Repeat("  ", level)
-
-	t := slice.Index(0).Type()
-	headers := []string{}
-	headerNames := []string{}
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		tag := f.Tag.Get("table")
-		if tag == "-" || tag == "" {
-			continue
+	for i, line := range layout.Lines(fmt.Sprint(v)) {
+		if i > 0 {
+			prefix = strings.Repeat(" ", width)
 		}
-		headers = append(headers, f.Name)
-		headerNames = append(headerNames, tag)
+		fmt.Fprintf(r.w, "%s%s\n", prefix, line)
 	}
+}
 
-	if len(headers) == 0 {
-		return
-	}
+// inline lays out v on a single line, for map keys.
+func (r *TextRenderer) inline(v any) string {
+	layout := r.layout
+	layout.Width = 0
+	return strings.Join(layout.Lines(fmt.Sprint(v)), " ")
+}
 
-	// Determine column widths
-	colWidths := make([]int, len(headers))
-	for i, name := range headerNames {
-		colWidths[i] = runewidth.StringWidth(name)
-	}
-	for i := 0; i < slice.Len(); i++ {
-		elem := slice.Index(i)
-		for j, field := range headers {
-			val := elem.FieldByName(field)
-			str := fmt.Sprint(val.Interface())
-			l := runewidth.StringWidth(str)
-			if l > colWidths[j] {
-				colWidths[j] = l
-			}
-		}
-	}
+// renderTable renders a slice of structs as a table with the columns of
+// their table tags, fit to the width of the terminal.
+func (r *TextRenderer) renderTable(slice reflect.Value, level int) {
+	t, _ := tableType(slice)
+	columns := usedColumns(slice, tableColumns(t))
 
-	// Print headers using ANSI positioning
-	fmt.Fprint(r.w, indent)
-	currentPos := len(indent)
-	for i, name := range headerNames {
-		fmt.Fprint(r.w, name)
-		currentPos += runewidth.StringWidth(name)
-		// Move to next column position
-		nextPos := currentPos + colWidths[i] - runewidth.StringWidth(name) + 2
-		if i < len(headerNames)-1 {
-			fmt.Fprintf(r.w, "\033[%dC", nextPos-currentPos)
-			currentPos = nextPos
-		}
+	tbl := &table{style: r.style, layout: r.layout, truncate: r.truncate}
+	for _, c := range columns {
+		tbl.headers = append(tbl.headers, c.Header)
 	}
-	fmt.Fprintln(r.w)
-
-	// Print separator using ANSI positioning
-	fmt.Fprint(r.w, indent)
-	currentPos = len(indent)
-	for i, w := range colWidths {
-		fmt.Fprint(r.w, strings.Repeat("-", w))
-		currentPos += w
-		// Move to next column position
-		nextPos := currentPos + 2
-		if i < len(colWidths)-1 {
-			fmt.Fprintf(r.w, "\033[%dC", nextPos-currentPos)
-			currentPos = nextPos
-		}
-	}
-	fmt.Fprintln(r.w)
-
-	// Print rows using ANSI positioning
 	for i := 0; i < slice.Len(); i++ {
-		elem := slice.Index(i)
-		fmt.Fprint(r.w, indent)
-		currentPos := len(indent)
-		for j, field := range headers {
-			val := elem.FieldByName(field)
-			cellContent := fmt.Sprint(val.Interface())
-			fmt.Fprint(r.w, cellContent)
-			currentPos += runewidth.StringWidth(cellContent)
-			// Move to next column position
-			nextPos := currentPos + colWidths[j] - runewidth.StringWidth(cellContent) + 2
-			if j < len(headers)-1 {
-				fmt.Fprintf(r.w, "\033[%dC", nextPos-currentPos)
-				currentPos = nextPos
+		row := make([]string, len(columns))
+		for j, c := range columns {
+			if v := c.cell(slice.Index(i)); v != nil {
+				row[j] = fmt.Sprint(v)
 			}
 		}
-		fmt.Fprintln(r.w)
+		tbl.rows = append(tbl.rows, row)
 	}
+	tbl.write(r.w, strings.Repeat("  ", level))
 }
@@ -0,0 +1,277 @@
+// Package settings reads and writes the CLI's configuration file, which holds
+// named profiles of settings, and resolves the settings in effect for a run.
+//
+// Most settings are named after the flag they give a default for, such as
+// output-format or tradition. Settings are taken, from highest precedence to
+// lowest, from:
+//
+//   - the command line flags
+//   - SEFARIA_* environment variables, such as SEFARIA_OUTPUT_FORMAT
+//   - the profile in use, from the configuration file
+//   - the built in defaults
+//
+// The configuration file is YAML:
+//
+//	profile: default
+//	profiles:
+//	  default:
+//	    output-format: text
+//	    tradition: ashkenaz
+//	    versions:
+//	      english: The Koren Jerusalem Bible
+//	  israel:
+//	    timezone: Asia/Jerusalem
+//	    diaspora: false
+package settings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the profile used when no other is chosen.
+const DefaultProfile = "default"
+
+// EnvPrefix starts the names of the environment variables that override
+// settings.
+const EnvPrefix = "SEFARIA_"
+
+// versionsKey starts the keys of the default versions, such as
+// versions.english.
+const versionsKey = "versions."
+
+// ErrUnknownKey is returned for a key that is not a setting.
+var ErrUnknownKey = errors.New("unknown setting")
+
+// Key describes a setting a profile may hold.
+type Key struct {
+	Name string
+	Desc string
+	Bool bool
+}
+
+// Keys are the settings a profile may hold. The default versions are held
+// under versions.<language>, which is not listed.
+var Keys = []Key{
+	{Name: "api-endpoint", Desc: "the Sefaria API to send requests to"},
+	{Name: "cache-dir", Desc: "the directory completions are cached in"},
+	{Name: "output-format", Desc: "output format (text, bilingual, json, yaml, xml, csv, markdown, html)"},
+	{Name: "truncate", Desc: "cut table cells short rather than wrapping them", Bool: true},
+	{Name: "log-level", Desc: "log level (debug, info, warn, error)"},
+	{Name: "log-format", Desc: "log format (text, json, console)"},
+	{Name: "no-bidi", Desc: "disable bidi text handling", Bool: true},
+	{Name: "bidi-strategy", Desc: "how RTL text is marked in json, csv and markdown output"},
+	{Name: "timezone", Desc: "the timezone calendars are given for"},
+	{Name: "diaspora", Desc: "use the diaspora calendar", Bool: true},
+	{Name: "tradition", Desc: "the reading tradition (ashkenaz, sefard, mizrahi)"},
+}
+
+// Lookup returns the setting named name. Keys of default versions, such as
+// versions.english, are settings as well.
+func Lookup(name string) (Key, error) {
+	if lang, ok := strings.CutPrefix(name, versionsKey); ok && lang != "" {
+		return Key{Name: name, Desc: "the default " + lang + " version"}, nil
+	}
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	return Key{}, fmt.Errorf("%w: %s", ErrUnknownKey, name)
+}
+
+// Check reports whether value is a valid value for the setting.
+func (k Key) Check(value string) error {
+	if k.Bool && value != "" {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false", k.Name)
+		}
+	}
+	return nil
+}
+
+// Env returns the environment variable that overrides the setting, such as
+// SEFARIA_OUTPUT_FORMAT for output-format.
+func (k Key) Env() string {
+	r := strings.NewReplacer("-", "_", ".", "_")
+	return EnvPrefix + strings.ToUpper(r.Replace(k.Name))
+}
+
+// Profile is a named set of settings.
+type Profile struct {
+	Values   map[string]string `yaml:",inline"`
+	Versions map[string]string `yaml:"versions,omitempty"`
+}
+
+// Get returns the value of a setting, and whether the profile holds it.
+func (p *Profile) Get(key string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	if lang, ok := strings.CutPrefix(key, versionsKey); ok {
+		v, ok := p.Versions[lang]
+		return v, ok
+	}
+	v, ok := p.Values[key]
+	return v, ok
+}
+
+// Set sets a setting. An empty value removes it.
+func (p *Profile) Set(key, value string) {
+	m := &p.Values
+	if lang, ok := strings.CutPrefix(key, versionsKey); ok {
+		m, key = &p.Versions, lang
+	}
+	if value == "" {
+		delete(*m, key)
+		return
+	}
+	if *m == nil {
+		*m = make(map[string]string)
+	}
+	(*m)[key] = value
+}
+
+// Keys returns the keys of the settings the profile holds, sorted.
+func (p *Profile) Keys() []string {
+	if p == nil {
+		return nil
+	}
+	keys := slices.Collect(maps.Keys(p.Values))
+	for lang := range p.Versions {
+		keys = append(keys, versionsKey+lang)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// File is the configuration file.
+type File struct {
+	// Profile is the profile used when none is chosen with a flag or the
+	// environment.
+	Profile  string              `yaml:"profile,omitempty"`
+	Profiles map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// Path returns where the configuration file is kept: the file named by
+// SEFARIA_CONFIG if it is set, otherwise sefaria/config.yaml in
+// XDG_CONFIG_HOME, which defaults to ~/.config.
+func Path() (string, error) {
+	if path := os.Getenv(EnvPrefix + "CONFIG"); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot find home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "sefaria", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path. A missing file is an empty
+// one.
+func Load(path string) (*File, error) {
+	f := &File{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Save writes the configuration file to path, creating its directory if
+// needed.
+func (f *File) Save(path string) error {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// Active returns the name of the profile in use. It is name if that is not
+// empty, otherwise the one named by SEFARIA_PROFILE, the file's own choice, or
+// DefaultProfile, in that order.
+func (f *File) Active(name string) string {
+	for _, n := range []string{name, os.Getenv(EnvPrefix + "PROFILE"), f.Profile} {
+		if n != "" {
+			return n
+		}
+	}
+	return DefaultProfile
+}
+
+// Get returns the profile called name, creating it if create is set. It
+// returns nil for a missing profile otherwise.
+func (f *File) Get(name string, create bool) *Profile {
+	p := f.Profiles[name]
+	if p == nil && create {
+		p = &Profile{}
+		if f.Profiles == nil {
+			f.Profiles = make(map[string]*Profile)
+		}
+		f.Profiles[name] = p
+	}
+	return p
+}
+
+// Value is a setting in effect.
+type Value struct {
+	Key   string `json:"key" table:"Key"`
+	Value string `json:"value" table:"Value"`
+	// Source is where the value came from: the environment variable or the
+	// profile that set it.
+	Source string `json:"source" table:"Source"`
+}
+
+// Resolve returns the settings in effect under the profile called name:
+// those it holds, overridden by the environment, sorted by key.
+func (f *File) Resolve(name string) []Value {
+	values := make(map[string]Value)
+	p := f.Get(name, false)
+	for _, key := range p.Keys() {
+		v, _ := p.Get(key)
+		values[key] = Value{Key: key, Value: v, Source: "profile " + name}
+	}
+
+	for _, k := range Keys {
+		if v, ok := os.LookupEnv(k.Env()); ok {
+			values[k.Name] = Value{Key: k.Name, Value: v, Source: k.Env()}
+		}
+	}
+	for _, env := range os.Environ() {
+		env, v, _ := strings.Cut(env, "=")
+		if lang, ok := strings.CutPrefix(env, EnvPrefix+"VERSIONS_"); ok && lang != "" {
+			key := versionsKey + strings.ToLower(lang)
+			values[key] = Value{Key: key, Value: v, Source: env}
+		}
+	}
+
+	out := slices.Collect(maps.Values(values))
+	slices.SortFunc(out, func(a, b Value) int { return strings.Compare(a.Key, b.Key) })
+	return out
+}
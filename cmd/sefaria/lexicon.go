@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+var (
+	cmdLexicon = &cobra.Command{
+		Use:   "lexicon",
+		Short: "Look up words in Sefaria's dictionaries",
+		Long: `Lexicon commands look up Hebrew and Aramaic words in Sefaria's dictionaries.
+
+Sefaria includes several lexicons, such as the BDB Dictionary, Jastrow and
+Klein Dictionary. A word is looked up in all of them at once.
+
+These commands let you:
+• Look up the entries for a word
+• Complete a partial word from the words a lexicon knows
+
+Examples:
+  sefaria lexicon lookup "שלום"
+  sefaria lexicon lookup "בראשית" --lookup-ref "Genesis 1:1"
+  sefaria lexicon complete "שלו"
+  sefaria lexicon complete "של" "Jastrow Dictionary"
+`,
+	}
+
+	optsLexiconLookup = &struct {
+		*sefaria.LexiconGetOptions
+	}{}
+	cmdLexiconLookup = &cobra.Command{
+		Use:     "lookup [word]",
+		Aliases: []string{"get"},
+		Short:   "Look up the dictionary entries for a word",
+		Long: `Look up the dictionary entries for a Hebrew or Aramaic word.
+
+The word may be written with or without vowels. A phrase may be looked up as a
+whole, or split into its words.
+
+Arguments:
+  word    The word to look up, such as "שלום"
+
+Options:
+  --lookup-ref          A ref the word appears in, to pick the entries that fit it
+  --never-split         Never split a phrase into its words
+  --always-split        Always look up each word of a phrase as well
+  --always-consonants   Look the word up by its consonants only
+
+Examples:
+  sefaria lexicon lookup "שלום"
+  sefaria lexicon lookup "בְּרֵאשִׁית" --always-consonants
+  sefaria lexicon lookup "ברא" --lookup-ref "Genesis 1:1" --output-format=yaml
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := client.Lexicon.Get(cmd.Context(), args[0], optsLexiconLookup.LexiconGetOptions)
+			if err != nil {
+				return fmt.Errorf("cannot look up word: %w", err)
+			}
+			renderer.Render(entries)
+			return nil
+		},
+	}
+
+	optsLexiconComplete = &struct {
+		*sefaria.LexiconCompletionsOptions
+	}{}
+	cmdLexiconComplete = &cobra.Command{
+		Use:     "complete [word] [lexicon]",
+		Aliases: []string{"completions"},
+		Short:   "Complete a partial word from the dictionaries",
+		Long: `Complete a partial Hebrew or Aramaic word from the words in Sefaria's dictionaries.
+
+Each completion is given with the form of the word the dictionary lists it
+under.
+
+Arguments:
+  word       The start of the word to complete, such as "שלו"
+  lexicon    Only complete from this lexicon, such as "Jastrow Dictionary"
+
+Options:
+  --limit    Number of completions to return
+
+Examples:
+  sefaria lexicon complete "שלו"
+  sefaria lexicon complete "של" "Jastrow Dictionary" --limit 5
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var lexicon string
+			if len(args) > 1 {
+				lexicon = args[1]
+			}
+
+			completions, err := client.Lexicon.Completions(cmd.Context(), args[0], lexicon, optsLexiconComplete.LexiconCompletionsOptions)
+			if err != nil {
+				return fmt.Errorf("cannot get completions: %w", err)
+			}
+			renderer.Render(completions)
+			return nil
+		},
+	}
+)
+
+func init() {
+	if err := gpflag.ParseTo(optsLexiconLookup, cmdLexiconLookup.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	if err := cmdLexiconLookup.RegisterFlagCompletionFunc("lookup-ref", completeRef); err != nil {
+		panic("cannot activate flag completion")
+	}
+	if err := gpflag.ParseTo(optsLexiconComplete, cmdLexiconComplete.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	cmdLexicon.AddCommand(cmdLexiconLookup, cmdLexiconComplete)
+
+	root.AddCommand(cmdLexicon)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+var (
+	optsLinker = &struct {
+		Title       string `flag:"title" desc:"an optional title to search alongside the text"`
+		WithText    bool   `flag:"with-text" desc:"include the text of each detected ref (requires --list)"`
+		MaxSegments int    `flag:"max-segments" desc:"maximum number of segments of text per ref"`
+		List        bool   `flag:"list" desc:"render the detected refs instead of annotating the text"`
+	}{}
+
+	cmdLinker = &cobra.Command{
+		Use:   "linker",
+		Short: "Detect citations of texts in free text read from stdin",
+		Long: `Detect citations of Jewish texts in arbitrary text using Sefaria's linker.
+
+The linker reads text from stdin and sends it to Sefaria, which finds citations
+such as "Genesis 1:1", "Berakhot 2a" or "רש״י על בראשית א:א" and resolves them
+to canonical refs.
+
+By default the input is written back out with every detected citation
+annotated in place with the ref it resolves to:
+
+  see Gen. 1:1  →  see [[Gen. 1:1|Genesis 1:1]]
+
+Ambiguous citations are annotated with every candidate ref, separated by " | ".
+Citations that could not be resolved are left untouched.
+
+Options:
+  --title         An optional title to search alongside the text
+  --list          Render the detected refs with the selected output format
+                  instead of annotating the text
+  --with-text     Include the text of each detected ref (with --list)
+  --max-segments  Maximum number of segments of text per ref (with --with-text)
+
+Examples:
+  # Annotate a paragraph
+  echo "As Rashi explains on Genesis 1:1" | sefaria linker
+
+  # List every ref found in a file as YAML
+  sefaria linker --list --output-format=yaml < drasha.txt
+
+  # Include the text of each ref
+  sefaria linker --list --with-text < drasha.txt
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("cannot read input: %w", err)
+			}
+			text := string(input)
+			if strings.TrimSpace(text) == "" {
+				return fmt.Errorf("no text to link")
+			}
+
+			result, err := client.Linker.FindRefs(cmd.Context(), text, &sefaria.FindRefsOptions{
+				Title:       optsLinker.Title,
+				WithText:    optsLinker.WithText,
+				MaxSegments: optsLinker.MaxSegments,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot find refs: %w", err)
+			}
+
+			if optsLinker.List {
+				renderer.Render(result)
+				return nil
+			}
+
+			_, err = io.WriteString(cmd.OutOrStdout(), annotateRefs(text, result.Body))
+			return err
+		},
+	}
+)
+
+// annotateRefs rewrites text so that every found ref is followed by the ref
+// it resolves to, wiki-link style.
+func annotateRefs(text string, refs []sefaria.FoundRef) string {
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		if ref.Start < last {
+			continue
+		}
+		b.WriteString(text[last:ref.Start])
+		b.WriteString("[[")
+		b.WriteString(ref.Text)
+		b.WriteString("|")
+		b.WriteString(strings.Join(ref.Candidates, " | "))
+		b.WriteString("]]")
+		last = ref.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func init() {
+	if err := gpflag.ParseTo(optsLinker, cmdLinker.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	root.AddCommand(cmdLinker)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria/citation"
+	"github.com/ryanfaerman/go-sefaria/linkify"
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+// linkifyFormats are the formats linkify rewrites, by the file extensions
+// that pick them.
+var linkifyFormats = map[string]string{
+	".html":     "html",
+	".htm":      "html",
+	".xhtml":    "html",
+	".md":       "markdown",
+	".markdown": "markdown",
+	".mdown":    "markdown",
+	".mkd":      "markdown",
+}
+
+var (
+	optsLinkify = &struct {
+		Format   string `flag:"format" desc:"the format of the document: html or markdown (default: from the file extension, else markdown)"`
+		Offline  bool   `flag:"offline" desc:"find citations with the offline detector instead of Sefaria's linker"`
+		Catalog  string `flag:"catalog" desc:"the title catalog the offline detector uses (default: catalog.json in the cache directory)"`
+		Tooltips bool   `flag:"tooltips" desc:"give each link a tooltip holding the text of the first segment of its ref"`
+		BaseURL  string `flag:"base-url" desc:"the site links point to (default: https://www.sefaria.org)"`
+	}{}
+
+	cmdLinkify = &cobra.Command{
+		Use:   "linkify [file]",
+		Short: "Turn the citations in an HTML or Markdown document into links",
+		Long: `Rewrite an HTML or Markdown document so that every citation of a text
+becomes a link to it on sefaria.org.
+
+The document is read from the file given, or from stdin when there is none
+or it is "-", and the rewritten document is written to stdout. Everything
+but the linked citations is written back out as it was. Text that is
+already a link, and text inside code, is left alone.
+
+Citations are found with Sefaria's linker, or with --offline by matching
+the titles of Sefaria's catalog. The catalog is downloaded the first time
+it is needed and kept in the cache directory, so later runs need no network
+connection.
+
+Arguments:
+  file  The document to rewrite (default: stdin)
+
+Options:
+  --format    The format of the document, html or markdown. It is taken
+              from the file's extension when not given, and is markdown
+              for stdin.
+  --offline   Find citations with the offline detector
+  --catalog   The file the offline detector's title catalog is kept in
+  --tooltips  Give each link a tooltip holding the text of the first
+              segment of its ref. This fetches each ref from Sefaria,
+              even with --offline.
+  --base-url  The site links point to
+
+Examples:
+  # Link the citations in a Markdown file without asking Sefaria
+  sefaria linkify notes.md --offline
+
+  # Link a blog post, with the text of each citation as a tooltip
+  sefaria linkify post.html --tooltips > post.linked.html
+
+  # Read the document from stdin
+  cat draft.txt | sefaria linkify --format=markdown
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "-"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			format, err := linkifyFormat(name, optsLinkify.Format)
+			if err != nil {
+				return err
+			}
+
+			src, err := readDocument(cmd, name)
+			if err != nil {
+				return fmt.Errorf("cannot read document: %w", err)
+			}
+
+			finder, err := linkifyFinder(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			opts := &linkify.Options{BaseURL: optsLinkify.BaseURL}
+			if optsLinkify.Tooltips {
+				opts.Tooltip = linkify.TextTooltips(client.Text)
+			}
+
+			var out string
+			switch format {
+			case "html":
+				out, err = linkify.HTML(cmd.Context(), src, finder, opts)
+			default:
+				out, err = linkify.Markdown(cmd.Context(), src, finder, opts)
+			}
+			if err != nil {
+				return fmt.Errorf("cannot link citations: %w", err)
+			}
+
+			_, err = io.WriteString(cmd.OutOrStdout(), out)
+			return err
+		},
+	}
+)
+
+// linkifyFormat returns the format of the document name: the one asked for,
+// else the one its extension picks, else markdown.
+func linkifyFormat(name, format string) (string, error) {
+	if format != "" {
+		switch format = strings.ToLower(format); format {
+		case "html", "markdown":
+			return format, nil
+		case "md":
+			return "markdown", nil
+		}
+		return "", fmt.Errorf("cannot linkify %q documents, only html and markdown", format)
+	}
+	if f, ok := linkifyFormats[strings.ToLower(filepath.Ext(name))]; ok {
+		return f, nil
+	}
+	return "markdown", nil
+}
+
+// readDocument reads the file name, or stdin when it is "-".
+func readDocument(cmd *cobra.Command, name string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if name == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	return string(data), err
+}
+
+// linkifyFinder returns the offline detector with --offline, and Sefaria's
+// linker otherwise.
+func linkifyFinder(ctx context.Context) (linkify.Finder, error) {
+	if !optsLinkify.Offline {
+		return linkify.Linker(client.Linker, nil), nil
+	}
+
+	path := optsLinkify.Catalog
+	if path == "" {
+		var err error
+		if path, err = catalogPath(); err != nil {
+			return nil, err
+		}
+	}
+	catalog, err := citation.LoadCatalog(ctx, client, path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load title catalog: %w", err)
+	}
+	return linkify.Detector(citation.NewDetector(catalog)), nil
+}
+
+// catalogPath returns where the title catalog is kept: in the cache-dir
+// setting, else in the sefaria directory of the user's cache directory.
+func catalogPath() (string, error) {
+	if dir := setting("cache-dir"); dir != "" {
+		return filepath.Join(dir, "catalog.json"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find a cache directory for the title catalog, use --catalog: %w", err)
+	}
+	return filepath.Join(dir, "sefaria", "catalog.json"), nil
+}
+
+func init() {
+	if err := gpflag.ParseTo(optsLinkify, cmdLinkify.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	if err := cmdLinkify.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"html", "markdown"}, cobra.ShellCompDirectiveNoFileComp)); err != nil {
+		panic("cannot activate flag completion")
+	}
+	root.AddCommand(cmdLinkify)
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/ryanfaerman/go-sefaria"
 	"github.com/ryanfaerman/go-sefaria/bidi"
+	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/query"
 	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/render"
 	"github.com/ryanfaerman/go-sefaria/cmd/sefaria/internal/version"
 	"github.com/spf13/cobra"
@@ -16,9 +22,19 @@ type Config struct {
 	LogLevel  string `flag:"log-level" desc:"(debug, info, warn, error, fatal, panic)"`
 	LogFormat string `flag:"log-format" desc:"log format (text, json, console)"`
 
-	OutputFormat string `flag:"output-format f" desc:"output format (text, json, yaml, xml, csv)"`
+	OutputFormat string `flag:"output-format f" desc:"output format (text, bilingual, json, yaml, xml, csv, markdown, html, template)"`
+	Template     string `flag:"template" desc:"a Go template to render output with; implies --output-format=template"`
+	TemplateFile string `flag:"template-file" desc:"a file holding a Go template to render output with"`
 
-	DisableBidi bool `flag:"no-bidi" desc:"disable bidi text handling"`
+	Truncate bool `flag:"truncate" desc:"cut table cells short to fit the terminal, rather than wrapping them"`
+
+	DisableBidi  bool   `flag:"no-bidi" desc:"disable bidi text handling"`
+	BidiStrategy string `flag:"bidi-strategy" desc:"how RTL text is marked in json, csv and markdown output (marks, isolates, html, raw)"`
+
+	Fields string `flag:"fields" desc:"comma separated paths of the fields to output, such as title.en,ref"`
+	Filter string `flag:"filter" desc:"only output values matching an expression, such as 'category == \"Daf Yomi\"'"`
+
+	Profile string `flag:"profile" desc:"the profile of the config file to use (default: default)"`
 }
 
 var (
@@ -27,6 +43,7 @@ var (
 		LogLevel:     "warn",
 		LogFormat:    "console",
 		OutputFormat: "json",
+		BidiStrategy: "marks",
 	}
 	renderer render.Renderer
 	logger   *slog.Logger
@@ -50,7 +67,8 @@ access to Sefaria's API, allowing you to:
 • Look up terms and get autocomplete suggestions
 
 The tool supports multiple output formats (JSON, YAML, XML, CSV, text) and
-includes comprehensive logging options for debugging and monitoring.
+includes comprehensive logging options for debugging and monitoring. Defaults
+for flags may be kept in profiles of a config file; see "sefaria config".
 
 Examples:
   sefaria terms completions "torah"
@@ -64,19 +82,49 @@ For help topics, use:
   sefaria help <topic>
 `,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadSettings(); err != nil {
+				return err
+			}
+			if err := applySettings(cmd); err != nil {
+				return err
+			}
+
 			var err error
 			logger, err = NewLogger(config.LogFormat, config.LogLevel, "")
 			if err != nil {
 				return fmt.Errorf("cannot create logger: %w", err)
 			}
 
-			w := cmd.OutOrStdout()
-			if !config.DisableBidi {
-				w = bidi.NewWriter(w, true)
+			strategy, err := bidi.ParseStrategy(config.BidiStrategy)
+			if err != nil {
+				return err
+			}
+
+			q, err := query.Parse(config.Fields, config.Filter)
+			if err != nil {
+				return err
 			}
-			renderer = render.NewRenderer(config.OutputFormat, w)
 
-			client = sefaria.NewClient(sefaria.WithLogger(logger))
+			tmpl, err := parseTemplate(cmd)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			renderer = render.NewRenderer(config.OutputFormat, w, render.Options{
+				Width:    render.TerminalWidth(w),
+				Truncate: config.Truncate,
+				Bidi:     !config.DisableBidi,
+				Strategy: strategy,
+				Template: tmpl,
+				Query:    q,
+			})
+
+			opts := []sefaria.ClientOption{sefaria.WithLogger(logger)}
+			if endpoint := setting("api-endpoint"); endpoint != "" {
+				opts = append(opts, sefaria.WithAPIEndpoint(endpoint))
+			}
+			client = sefaria.NewClient(opts...)
 
 			return nil
 		},
@@ -86,6 +134,37 @@ For help topics, use:
 	}
 )
 
+// parseTemplate parses the template given with --template or
+// --template-file. Giving one chooses the template format, unless another
+// format was asked for on the command line.
+func parseTemplate(cmd *cobra.Command) (*template.Template, error) {
+	text, name := config.Template, "template"
+	switch {
+	case text != "" && config.TemplateFile != "":
+		return nil, errors.New("cannot use both --template and --template-file")
+	case config.TemplateFile != "":
+		data, err := os.ReadFile(config.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read template: %w", err)
+		}
+		text, name = string(data), filepath.Base(config.TemplateFile)
+	case text == "":
+		if strings.EqualFold(config.OutputFormat, "template") {
+			return nil, errors.New("the template format needs --template or --template-file")
+		}
+		return nil, nil
+	}
+
+	if !cmd.Flags().Changed("output-format") {
+		config.OutputFormat = "template"
+	}
+	tmpl, err := render.ParseTemplate(name, text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
 func init() {
 	if err := gpflag.ParseTo(config, root.PersistentFlags()); err != nil {
 		panic("cannot activate command flags")
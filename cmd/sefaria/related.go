@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+var (
+	cmdRelated = &cobra.Command{
+		Use:   "related",
+		Short: "Find content related to a ref",
+		Long: `Related commands find everything in Sefaria that is connected to a ref.
+
+Texts in Sefaria are linked to one another: a verse of Torah is linked to the
+commentaries on it, to the Talmud passages that quote it, and to the source
+sheets and topics that use it.
+
+These commands let you:
+• Get all of the content related to a ref at once
+• List the links from a ref to other texts
+
+Examples:
+  sefaria related get "Genesis 1:1"
+  sefaria related links "Genesis 1:1"
+  sefaria related links "Berakhot 2a" --with-text
+`,
+	}
+
+	cmdRelatedGet = &cobra.Command{
+		Use:   "get [ref]",
+		Short: "Get all content related to a ref",
+		Long: `Get all of the content related to a ref.
+
+This includes links to other texts, source sheets, notes, media, manuscripts
+and topics, grouped by kind.
+
+Arguments:
+  ref    The ref to find related content for, such as "Genesis 1:1"
+
+Examples:
+  sefaria related get "Genesis 1:1"
+  sefaria related get "Berakhot 2a" --output-format=yaml
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			related, err := client.Related.Get(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				return fmt.Errorf("cannot get related content: %w", err)
+			}
+			renderer.Render(related)
+			return nil
+		},
+	}
+
+	optsRelatedLinks = &struct {
+		*sefaria.RelatedLinksOptions
+	}{}
+	cmdRelatedLinks = &cobra.Command{
+		Use:   "links [ref]",
+		Short: "List the links from a ref to other texts",
+		Long: `List the links from a ref to other texts, such as commentaries and quotations.
+
+Each link gives the ref it leads to, its category and the kind of connection,
+such as "commentary" or "quotation".
+
+Arguments:
+  ref    The ref to list links from, such as "Genesis 1:1"
+
+Options:
+  --with-text           Include the text of each link
+  --with-sheet-links    Include links from source sheets
+
+Examples:
+  sefaria related links "Genesis 1:1"
+  sefaria related links "Genesis 1:1" --with-text --output-format=json-pretty
+  sefaria related links "Berakhot 2a" --with-sheet-links
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			links, err := client.Related.Links(cmd.Context(), strings.Join(args, " "), optsRelatedLinks.RelatedLinksOptions)
+			if err != nil {
+				return fmt.Errorf("cannot get links: %w", err)
+			}
+			renderer.Render(links)
+			return nil
+		},
+	}
+)
+
+func init() {
+	if err := gpflag.ParseTo(optsRelatedLinks, cmdRelatedLinks.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	cmdRelated.AddCommand(cmdRelatedGet, cmdRelatedLinks)
+
+	root.AddCommand(cmdRelated)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+var textFormats = []sefaria.TextFormat{
+	sefaria.FormatDefault,
+	sefaria.FormatTextOnly,
+	sefaria.FormatStripOnlyFootnotes,
+	sefaria.FormatWrapAllEntities,
+}
+
+var (
+	cmdText = &cobra.Command{
+		Use:   "text",
+		Short: "Get texts, versions and translations from Sefaria",
+		Long: `Text commands give access to the texts in Sefaria's library.
+
+Texts are addressed by refs, such as "Genesis 1:1", "Berakhot 2a" or
+"Mishnah Peah 1:1-3". Ranges are given with a hyphen, and a ref without a
+segment, such as "Genesis 1", returns the whole section.
+
+These commands let you:
+• Read a text in one or more versions and languages
+• List the versions of a text that Sefaria has
+• Browse the translations available in a language
+• Discover a random text
+• Find manuscript images of a passage
+
+Examples:
+  sefaria text get "Genesis 1:1"
+  sefaria text get "Genesis 1:1-5" --version english --version hebrew
+  sefaria text versions Genesis
+  sefaria text translations en
+  sefaria text random --category Tanakh
+  sefaria text manuscripts "Berakhot 2a"
+`,
+	}
+
+	optsTextGet = &struct {
+		Versions    []string `flag:"version" desc:"a version to get, as language or language|title (repeatable)"`
+		Format      string   `flag:"format" desc:"text format (default, text_only, strip_only_footnotes, wrap_all_entities)"`
+		FillMissing bool     `flag:"fill-missing" desc:"fill segments missing from a version with those of another version"`
+	}{
+		Format: string(sefaria.FormatDefault),
+	}
+
+	cmdTextGet = &cobra.Command{
+		Use:   "get [ref]",
+		Short: "Get the text of a ref",
+		Long: `Get the text of a ref in one or more versions.
+
+By default Sefaria returns its primary version of the text. Use --version to
+choose versions by language, or by language and title separated by "|". The
+flag may be given more than once to get several versions at once.
+
+The ref may be quoted or given as several arguments, which are joined with
+spaces.
+
+Arguments:
+  ref    The ref to get, such as "Genesis 1:1", "Genesis 1:1-5" or "Berakhot 2a"
+
+Options:
+  --version       A version to get, as a language ("english", "hebrew", "source")
+                  or a language and title ("english|The Koren Jerusalem Bible")
+  --format        How the text is formatted:
+                    default               the text as Sefaria stores it
+                    text_only             the text without any markup
+                    strip_only_footnotes  the text without its footnotes
+                    wrap_all_entities     the text with refs and topics linked
+  --fill-missing  Fill segments missing from a version with those of another
+
+Examples:
+  # Get a verse in Sefaria's primary version
+  sefaria text get "Genesis 1:1"
+
+  # Get a range in English and Hebrew, side by side
+  sefaria text get Genesis 1:1-5 --version english --version hebrew -f bilingual
+
+  # Get a specific translation without markup
+  sefaria text get "Exodus 20" --version "english|The Koren Jerusalem Bible" --format text_only
+
+  # Fill in segments a partial translation is missing
+  sefaria text get "Mishnah Peah 1" --version english --fill-missing
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := sefaria.TextFormat(optsTextGet.Format)
+			if !slices.Contains(textFormats, format) {
+				return fmt.Errorf("unknown text format: %s", optsTextGet.Format)
+			}
+
+			opts := &sefaria.TextOptions{
+				FillMissingSegments: optsTextGet.FillMissing,
+				Format:              format,
+			}
+			for _, v := range optsTextGet.Versions {
+				lang, title, _ := strings.Cut(v, "|")
+				opts.Versions = append(opts.Versions, sefaria.TextVersion{
+					Language: strings.TrimSpace(lang),
+					Title:    strings.TrimSpace(title),
+				})
+			}
+
+			text, err := client.Text.Get(cmd.Context(), strings.Join(args, " "), opts)
+			if err != nil {
+				return fmt.Errorf("cannot get text: %w", err)
+			}
+			renderer.Render(text)
+			return nil
+		},
+	}
+
+	cmdTextVersions = &cobra.Command{
+		Use:   "versions [title]",
+		Short: "List the versions of a text",
+		Long: `List every version Sefaria has of a text, in every language.
+
+Each version has a language and a title, which together can be passed to
+"sefaria text get --version" to read it.
+
+Arguments:
+  title    The title of the text, such as "Genesis" or "Berakhot"
+
+Examples:
+  sefaria text versions Genesis
+  sefaria text versions "Pirkei Avot" --output-format=yaml
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			versions, err := client.Text.Versions(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				return fmt.Errorf("cannot get versions: %w", err)
+			}
+			renderer.Render(versions)
+			return nil
+		},
+	}
+
+	cmdTextTranslations = &cobra.Command{
+		Use:   "translations [language]",
+		Short: "List the texts translated into a language",
+		Long: `List every text Sefaria has a translation of in a language, by category.
+
+Use "sefaria text languages" to see which languages are available.
+
+Arguments:
+  language    The code of the language, such as "en", "de" or "fr"
+
+Examples:
+  sefaria text translations en
+  sefaria text translations de --output-format=csv
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			translations, err := client.Text.Translations(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("cannot get translations: %w", err)
+			}
+			renderer.Render(translations)
+			return nil
+		},
+	}
+
+	cmdTextLanguages = &cobra.Command{
+		Use:   "languages",
+		Short: "List the languages texts are translated into",
+		Long: `List the codes of every language Sefaria has translations in.
+
+Examples:
+  sefaria text languages
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			languages, err := client.Text.Languages(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cannot get languages: %w", err)
+			}
+			renderer.Render(languages)
+			return nil
+		},
+	}
+
+	optsTextRandom = &struct {
+		Titles     []string `flag:"title" desc:"only pick from texts with this title (repeatable)"`
+		Categories []string `flag:"category" desc:"only pick from texts in this category (repeatable)"`
+	}{}
+
+	cmdTextRandom = &cobra.Command{
+		Use:   "random",
+		Short: "Get a random text",
+		Long: `Get a random segment of text, optionally limited to some titles or categories.
+
+Options:
+  --title       Only pick from texts with this title; may be repeated
+  --category    Only pick from texts in this category; may be repeated
+
+Examples:
+  sefaria text random
+  sefaria text random --category Tanakh
+  sefaria text random --title Genesis --title Exodus -f bilingual
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var opts *sefaria.RandomTextOptions
+			if len(optsTextRandom.Titles) > 0 || len(optsTextRandom.Categories) > 0 {
+				opts = &sefaria.RandomTextOptions{
+					Titles:     optsTextRandom.Titles,
+					Categories: optsTextRandom.Categories,
+				}
+			}
+
+			text, err := client.Text.Random(cmd.Context(), opts)
+			if err != nil {
+				return fmt.Errorf("cannot get random text: %w", err)
+			}
+			renderer.Render(text)
+			return nil
+		},
+	}
+
+	cmdTextManuscripts = &cobra.Command{
+		Use:   "manuscripts [ref]",
+		Short: "List manuscript images of a ref",
+		Long: `List the manuscript pages Sefaria has images of for a ref.
+
+Each page comes with links to its image and a thumbnail, and the manuscript it
+belongs to.
+
+Arguments:
+  ref    The ref to find manuscripts of, such as "Berakhot 2a"
+
+Examples:
+  sefaria text manuscripts "Berakhot 2a"
+  sefaria text manuscripts Genesis 1 --output-format=json-pretty
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRefArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manuscripts, err := client.Text.Manuscripts(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				return fmt.Errorf("cannot get manuscripts: %w", err)
+			}
+			renderer.Render(manuscripts)
+			return nil
+		},
+	}
+)
+
+func init() {
+	if err := gpflag.ParseTo(optsTextGet, cmdTextGet.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	if err := gpflag.ParseTo(optsTextRandom, cmdTextRandom.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	if err := cmdTextRandom.RegisterFlagCompletionFunc("title", completeRef); err != nil {
+		panic("cannot activate flag completion")
+	}
+	cmdText.AddCommand(cmdTextGet, cmdTextVersions, cmdTextTranslations, cmdTextLanguages, cmdTextRandom, cmdTextManuscripts)
+
+	root.AddCommand(cmdText)
+}
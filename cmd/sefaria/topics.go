@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/urfave/sflags/gen/gpflag"
+)
+
+var (
+	cmdTopics = &cobra.Command{
+		Use:   "topics",
+		Short: "Explore the topics texts are gathered under",
+		Long: `Topics commands explore Sefaria's topics: people, places, holidays, ideas
+and the other subjects that texts are gathered under.
+
+Topics are linked to the texts that discuss them and to each other, forming a
+graph: Shabbat is a holiday, Moses is a prophet, and so on. Each topic is
+known by a slug, such as "shabbat" or "moses".
+
+These commands let you:
+• List the topics Sefaria has
+• Get a topic with its description and sources
+• Walk the graph of links between topics
+• Find the topics that fit a set of refs
+• Discover a random topic
+
+Examples:
+  sefaria topics list --limit 20
+  sefaria topics get shabbat
+  sefaria topics graph shabbat
+  sefaria topics recommend "Genesis 1:1" "Exodus 20:8"
+  sefaria topics random
+`,
+	}
+
+	optsTopicsList = &struct {
+		Limit int `flag:"limit" desc:"number of topics to return. 0 = no limit."`
+	}{}
+	cmdTopicsList = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"all"},
+		Short:   "List the topics in Sefaria",
+		Long: `List the topics in Sefaria, with their slugs and titles.
+
+There are thousands of topics, so use --limit to see only some of them.
+
+Options:
+  --limit    Number of topics to return (0 = all of them)
+
+Examples:
+  sefaria topics list --limit 20
+  sefaria topics list --output-format=csv > topics.csv
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topics, err := client.Topics.All(cmd.Context(), optsTopicsList.Limit)
+			if err != nil {
+				return fmt.Errorf("cannot list topics: %w", err)
+			}
+			renderer.Render(topics)
+			return nil
+		},
+	}
+
+	cmdTopicsGet = &cobra.Command{
+		Use:   "get [slug]",
+		Short: "Get a topic by its slug",
+		Long: `Get a topic by its slug, with its titles, description and the sources linked to it.
+
+Arguments:
+  slug    The slug of the topic, such as "shabbat" or "moses"
+
+Examples:
+  sefaria topics get shabbat
+  sefaria topics get moses --output-format=yaml
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTopic,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic, err := client.Topics.Get(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("cannot get topic: %w", err)
+			}
+			renderer.Render(topic)
+			return nil
+		},
+	}
+
+	optsTopicsGraph = &struct {
+		LinkType string `flag:"link-type" desc:"the kind of link to follow, such as is-a or related-to"`
+	}{
+		LinkType: "is-a",
+	}
+	cmdTopicsGraph = &cobra.Command{
+		Use:   "graph [slug]",
+		Short: "Get the topics linked to a topic",
+		Long: `Get the topics linked to a topic, following one kind of link.
+
+By default the "is-a" links are followed, which lead from a topic to the
+broader topics it belongs to, such as from Shabbat to holidays.
+
+Arguments:
+  slug    The slug of the topic, such as "shabbat"
+
+Options:
+  --link-type    The kind of link to follow, such as "is-a" or "related-to"
+
+Examples:
+  sefaria topics graph shabbat
+  sefaria topics graph moses --link-type related-to
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTopic,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topics, err := client.Topics.Graph(cmd.Context(), args[0], optsTopicsGraph.LinkType)
+			if err != nil {
+				return fmt.Errorf("cannot get topic graph: %w", err)
+			}
+			renderer.Render(topics)
+			return nil
+		},
+	}
+
+	cmdTopicsRecommend = &cobra.Command{
+		Use:     "recommend [ref...]",
+		Aliases: []string{"recommended"},
+		Short:   "Find the topics that fit a set of refs",
+		Long: `Find the topics that fit one or more refs.
+
+Each ref must be given as its own argument, quoted if it holds spaces.
+
+Arguments:
+  ref    One or more refs, such as "Genesis 1:1" "Exodus 20:8"
+
+Examples:
+  sefaria topics recommend "Genesis 1:1"
+  sefaria topics recommend "Exodus 20:8" "Deuteronomy 5:12" --output-format=text
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRef,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topics, err := client.Topics.Recommended(cmd.Context(), args...)
+			if err != nil {
+				return fmt.Errorf("cannot get recommended topics: %w", err)
+			}
+			renderer.Render(topics)
+			return nil
+		},
+	}
+
+	cmdTopicsRandom = &cobra.Command{
+		Use:   "random",
+		Short: "Get random topics with a text from each",
+		Long: `Get a few random topics, each with a text linked to it.
+
+Examples:
+  sefaria topics random
+  sefaria topics random --output-format=text
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topics, err := client.Topics.Random(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("cannot get random topics: %w", err)
+			}
+			renderer.Render(topics)
+			return nil
+		},
+	}
+)
+
+func init() {
+	if err := gpflag.ParseTo(optsTopicsList, cmdTopicsList.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	if err := gpflag.ParseTo(optsTopicsGraph, cmdTopicsGraph.Flags()); err != nil {
+		panic("cannot activate command flags")
+	}
+	cmdTopics.AddCommand(cmdTopicsList, cmdTopicsGet, cmdTopicsGraph, cmdTopicsRecommend, cmdTopicsRandom)
+
+	root.AddCommand(cmdTopics)
+}
@@ -0,0 +1,119 @@
+package sefaria
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+	"github.com/ryanfaerman/go-sefaria/types"
+)
+
+type CollectionService service
+
+// CollectionTOC describes how a collection is presented when it is listed in
+// the Sefaria table of contents.
+type CollectionTOC struct {
+	Categories    []string    `json:"categories"`
+	Title         string      `json:"title"`
+	HeTitle       bidi.String `json:"heTitle"`
+	Description   string      `json:"description"`
+	HeDescription bidi.String `json:"heDescription"`
+	Dependence    string      `json:"dependence,omitempty"`
+}
+
+// Collection is a named group of sheets that share a slug. The sheets are
+// only populated when the collection is fetched by slug.
+type Collection struct {
+	Name        string `json:"name" table:"Name"`
+	Slug        string `json:"slug" table:"Slug" normalize:"-"`
+	Description string `json:"description"`
+	ImageURL    string `json:"imageUrl" normalize:"-"`
+	HeaderURL   string `json:"headerUrl" normalize:"-"`
+	WebsiteURL  string `json:"websiteUrl" normalize:"-"`
+	Listed      bool   `json:"listed"`
+
+	SheetCount   int        `json:"sheetCount" table:"Sheets"`
+	MemberCount  int        `json:"memberCount" table:"Members"`
+	LastModified types.Date `json:"lastModified"`
+
+	TOC *CollectionTOC `json:"toc,omitempty"`
+
+	PinnedSheets      []int          `json:"pinnedSheets"`
+	PinnedTags        []string       `json:"pinnedTags"`
+	ShowTagsByDefault bool           `json:"showTagsByDefault"`
+	Sheets            []SheetSummary `json:"sheets"`
+}
+
+// CollectionListing is the set of collections visible to the requester.
+// Requests made without authentication only ever see public collections.
+type CollectionListing struct {
+	Public  []Collection `json:"public"`
+	Private []Collection `json:"private"`
+}
+
+var ErrEmptyCollectionSlug = errors.New("collection slug cannot be empty")
+
+// List the collections available on Sefaria.
+func (s *CollectionService) List(ctx context.Context) (*CollectionListing, error) {
+	u := s.client.BaseURL.JoinPath("/collections")
+	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(CollectionListing)
+	_, err = s.client.Do(req, out)
+	return out, err
+}
+
+// Get the collection with the given slug along with summaries of its sheets.
+//
+// Arguments:
+//   - slug: the url slug of the collection, e.g. "sefaria-team-sheets"
+func (s *CollectionService) Get(ctx context.Context, slug string) (*Collection, error) {
+	if slug == "" {
+		return nil, ErrEmptyCollectionSlug
+	}
+
+	u := s.client.BaseURL.JoinPath("/collections", slug)
+	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(Collection)
+	_, err = s.client.Do(req, out)
+	return out, err
+}
+
+// Sources iterates over every source of every sheet in the collection, in the
+// order the sheets are listed. Each sheet is fetched in full as the iteration
+// reaches it, so breaking out of the loop early avoids the remaining requests.
+//
+// An error fetching the collection or a sheet is yielded once and ends the
+// iteration.
+func (s *CollectionService) Sources(ctx context.Context, slug string) iter.Seq2[SheetSource, error] {
+	return func(yield func(SheetSource, error) bool) {
+		collection, err := s.Get(ctx, slug)
+		if err != nil {
+			yield(SheetSource{}, err)
+			return
+		}
+
+		sheets := (*SheetService)(s)
+		for _, summary := range collection.Sheets {
+			sheet, err := sheets.Get(ctx, summary.ID)
+			if err != nil {
+				yield(SheetSource{}, err)
+				return
+			}
+			for _, source := range sheet.Sources {
+				if !yield(source, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -7,7 +7,9 @@ require (
 	github.com/go-playground/validator/v10 v10.28.0
 	github.com/google/go-querystring v1.1.0
 	github.com/hashicorp/go-retryablehttp v0.7.8
+	github.com/mattn/go-runewidth v0.0.16
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/net v0.44.0
 	golang.org/x/sys v0.36.0
 	golang.org/x/text v0.29.0
 )
@@ -20,6 +22,7 @@ require (
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/crypto v0.42.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,6 +3,8 @@ package sefaria
 import (
 	"context"
 	"net/http"
+
+	"github.com/google/go-querystring/query"
 )
 
 type IndexService service
@@ -34,8 +36,12 @@ func (s *IndexService) Get(ctx context.Context, title string) (*Index, error) {
 }
 
 type IndexShapeOptions struct {
-	Depth      int  `url:"depth,omitempty"`
-	Dependents bool `url:"dependents,omitempty"`
+	// How many levels of the text's structure to return. 0 returns all of
+	// them.
+	Depth int `url:"depth,omitempty" desc:"levels of structure to return. 0 = all."`
+
+	// Include the commentaries and other texts that depend on the text.
+	Dependents bool `url:"dependents,omitempty" desc:"include commentaries and other dependent texts"`
 }
 
 type Shape struct {
@@ -49,6 +55,15 @@ type Shape struct {
 
 func (s *IndexService) Shape(ctx context.Context, title string, opts *IndexShapeOptions) ([]Shape, error) {
 	u := s.client.BaseURL.JoinPath("/shape", title)
+
+	if opts != nil {
+		v, err := query.Values(opts)
+		if err != nil {
+			return nil, err
+		}
+		u.RawQuery = v.Encode()
+	}
+
 	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
@@ -10,10 +10,17 @@ import (
 type LexiconService service
 
 type LexiconGetOptions struct {
-	LookupRef        string `url:"lookup_ref,omitempty"`
-	NeverSplit       bool   `url:"never_split,omitempty"`
-	AlwaysSplit      bool   `url:"always_split,omitempty"`
-	AlwaysConsonants bool   `url:"always_consonants,omitempty"`
+	// A ref the word appears in, used to pick the entries that fit it.
+	LookupRef string `url:"lookup_ref,omitempty" desc:"a ref the word appears in, to pick the entries that fit it"`
+
+	// Never split a phrase into its words when looking it up.
+	NeverSplit bool `url:"never_split,omitempty" desc:"never split a phrase into its words"`
+
+	// Always look up each word of a phrase as well as the whole.
+	AlwaysSplit bool `url:"always_split,omitempty" desc:"always look up each word of a phrase"`
+
+	// Look the word up by its consonants, ignoring its vowels.
+	AlwaysConsonants bool `url:"always_consonants,omitempty" desc:"look the word up by its consonants only"`
 }
 
 type DictionaryEntry map[string]any
@@ -40,7 +47,8 @@ func (s *LexiconService) Get(ctx context.Context, word string, opts *LexiconGetO
 }
 
 type LexiconCompletionsOptions struct {
-	Limit int `url:"limit,omitempty"`
+	// Number of completions to return. 0 uses Sefaria's default.
+	Limit int `url:"limit,omitempty" desc:"number of completions to return"`
 }
 
 func (s *LexiconService) Completions(ctx context.Context, word string, lexicon string, opts *LexiconCompletionsOptions) ([][]string, error) {
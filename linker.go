@@ -0,0 +1,264 @@
+package sefaria
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/go-querystring/query"
+)
+
+type LinkerService service
+
+type FindRefsOptions struct {
+	// An optional title to search alongside the body text. Citations found in
+	// the title are reported separately from those in the body.
+	Title string `url:"-"`
+
+	// Include the text of each resolved ref in the response's RefData.
+	WithText bool `url:"with_text,omitempty"`
+
+	// The maximum number of segments of text to return per ref when WithText
+	// is set.
+	MaxSegments int `url:"max_segments,omitempty" validate:"gte=0"`
+
+	// How often to poll for the result when Sefaria processes the request
+	// asynchronously. Defaults to half a second.
+	PollInterval time.Duration `url:"-"`
+}
+
+// FoundRef is a single citation detected in a piece of text.
+type FoundRef struct {
+	// Start and End are the byte offsets of the citation within the text that
+	// was searched, so that text[Start:End] == Text.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// The citation exactly as it appears in the text.
+	Text string `json:"text" table:"Text"`
+
+	// The resolved ref. Empty when the citation could not be resolved or
+	// when it is ambiguous.
+	Ref string `json:"ref" table:"Ref" normalize:"-"`
+
+	// Parsed is the resolved ref broken down into its book and sections. It
+	// is nil whenever Ref is empty.
+	Parsed *Ref `json:"parsed,omitempty" normalize:"-"`
+
+	// Every ref the citation could resolve to. When there is more than one
+	// the citation is ambiguous and Ref is left empty.
+	Candidates []string `json:"candidates,omitempty" normalize:"-"`
+	Ambiguous  bool     `json:"ambiguous" table:"Ambiguous"`
+
+	// The language of the citation, either "en" or "he".
+	Language string `json:"language" table:"Language"`
+}
+
+// LinkedRef holds additional information about a ref found by the linker.
+type LinkedRef struct {
+	HeRef           string   `json:"heRef" normalize:"-"`
+	URL             string   `json:"url" normalize:"-"`
+	PrimaryCategory string   `json:"primaryCategory"`
+	English         []string `json:"en,omitempty"`
+	Hebrew          []string `json:"he,omitempty"`
+}
+
+type FindRefsResult struct {
+	Title []FoundRef `json:"title"`
+	Body  []FoundRef `json:"body"`
+
+	// RefData is keyed by ref and includes every ref found in either the
+	// title or the body.
+	RefData map[string]LinkedRef `json:"refData"`
+}
+
+var ErrEmptyLinkerText = errors.New("text to link cannot be empty")
+
+type findRefsRequest struct {
+	Text struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"text"`
+}
+
+type findRefsSpan struct {
+	StartChar  int      `json:"startChar"`
+	EndChar    int      `json:"endChar"`
+	Text       string   `json:"text" normalize:"-"`
+	LinkFailed bool     `json:"linkFailed"`
+	Refs       []string `json:"refs" normalize:"-"`
+}
+
+type findRefsSection struct {
+	Results []findRefsSpan       `json:"results"`
+	RefData map[string]LinkedRef `json:"refData"`
+}
+
+type findRefsResponse struct {
+	Title  *findRefsSection `json:"title"`
+	Body   *findRefsSection `json:"body"`
+	TaskID string           `json:"task_id"`
+}
+
+type asyncTask struct {
+	TaskID string           `json:"task_id"`
+	State  string           `json:"state"`
+	Ready  bool             `json:"ready"`
+	Result findRefsResponse `json:"result"`
+}
+
+// FindRefs detects citations of texts in arbitrary title and body text. Only
+// citations that the linker managed to resolve to at least one ref are
+// returned.
+//
+// Arguments:
+//   - text: the body text to search
+//   - opts: optional parameters
+func (s *LinkerService) FindRefs(ctx context.Context, text string, opts *FindRefsOptions) (*FindRefsResult, error) {
+	if text == "" {
+		return nil, ErrEmptyLinkerText
+	}
+	if opts == nil {
+		opts = &FindRefsOptions{}
+	}
+	if err := s.client.validateStruct(opts); err != nil {
+		return nil, err
+	}
+
+	u := s.client.BaseURL.JoinPath("/find-refs")
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = v.Encode()
+
+	body := new(findRefsRequest)
+	body.Text.Title = opts.Title
+	body.Text.Body = text
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(findRefsResponse)
+	if _, err := s.client.Do(req, res); err != nil {
+		return nil, err
+	}
+
+	if res.TaskID != "" {
+		res, err = s.wait(ctx, res.TaskID, opts.PollInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := &FindRefsResult{
+		RefData: make(map[string]LinkedRef),
+	}
+	if res.Title != nil {
+		out.Title = foundRefs(opts.Title, res.Title.Results)
+		for ref, data := range res.Title.RefData {
+			out.RefData[ref] = data
+		}
+	}
+	if res.Body != nil {
+		out.Body = foundRefs(text, res.Body.Results)
+		for ref, data := range res.Body.RefData {
+			out.RefData[ref] = data
+		}
+	}
+
+	return out, nil
+}
+
+// wait polls an asynchronous linker task until it is finished.
+func (s *LinkerService) wait(ctx context.Context, taskID string, interval time.Duration) (*findRefsResponse, error) {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	u := s.client.BaseURL.JoinPath("/async", taskID)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		task := new(asyncTask)
+		if _, err := s.client.Do(req, task); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case task.State == "FAILURE":
+			return nil, fmt.Errorf("linker task %s failed", taskID)
+		case task.Ready:
+			return &task.Result, nil
+		}
+	}
+}
+
+// foundRefs converts the spans returned by the linker, which are measured in
+// characters, into byte offsets within text.
+func foundRefs(text string, spans []findRefsSpan) []FoundRef {
+	offsets := runeOffsets(text)
+	out := make([]FoundRef, 0, len(spans))
+	for _, span := range spans {
+		if span.LinkFailed || len(span.Refs) == 0 {
+			continue
+		}
+		if span.StartChar < 0 || span.EndChar > len(offsets)-1 || span.StartChar > span.EndChar {
+			continue
+		}
+
+		found := FoundRef{
+			Start:      offsets[span.StartChar],
+			End:        offsets[span.EndChar],
+			Candidates: span.Refs,
+			Ambiguous:  len(span.Refs) > 1,
+		}
+		found.Text = text[found.Start:found.End]
+		found.Language = textLanguage(found.Text)
+		if !found.Ambiguous {
+			found.Ref = span.Refs[0]
+			if parsed, err := ParseRef(found.Ref); err == nil {
+				found.Parsed = &parsed
+			}
+		}
+		out = append(out, found)
+	}
+	return out
+}
+
+// runeOffsets returns the byte offset of every rune in s, followed by len(s).
+func runeOffsets(s string) []int {
+	offsets := make([]int, 0, utf8.RuneCountInString(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(s))
+}
+
+// textLanguage reports "he" for text containing Hebrew letters and "en"
+// otherwise.
+func textLanguage(s string) string {
+	for _, r := range s {
+		if unicode.Is(unicode.Hebrew, r) {
+			return "he"
+		}
+	}
+	return "en"
+}
@@ -0,0 +1,221 @@
+package sefaria
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkerBody holds Hebrew and an emoji before its later citations, so that
+// their character and byte offsets differ.
+const linkerBody = "כמו שכתוב בראשית א׳ א׳ 🙂 and as Rashi explains on Genesis 1:1, see also Exodus 2:3 (שמות ב׳ ג׳)."
+
+// charSpan returns the character offsets of sub within s, as the linker
+// gives them.
+func charSpan(t *testing.T, s, sub string) (start, end int) {
+	t.Helper()
+	i := strings.Index(s, sub)
+	require.GreaterOrEqual(t, i, 0, "%q not in %q", sub, s)
+	start = utf8.RuneCountInString(s[:i])
+	return start, start + utf8.RuneCountInString(sub)
+}
+
+func span(t *testing.T, s, sub string, refs ...string) findRefsSpan {
+	start, end := charSpan(t, s, sub)
+	return findRefsSpan{StartChar: start, EndChar: end, Text: sub, Refs: refs}
+}
+
+func newLinkerClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(WithAPIEndpoint(srv.URL))
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestFindRefs_ByteOffsets(t *testing.T) {
+	title := "עיון ב־Genesis 1:1"
+
+	client := newLinkerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/find-refs", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("with_text"))
+
+		var req findRefsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, linkerBody, req.Text.Body)
+		assert.Equal(t, title, req.Text.Title)
+
+		writeJSON(t, w, findRefsResponse{
+			Title: &findRefsSection{Results: []findRefsSpan{
+				span(t, title, "Genesis 1:1", "Genesis 1:1"),
+			}},
+			Body: &findRefsSection{
+				Results: []findRefsSpan{
+					span(t, linkerBody, "בראשית א׳ א׳", "Genesis 1:1"),
+					span(t, linkerBody, "Genesis 1:1", "Genesis 1:1"),
+					span(t, linkerBody, "Exodus 2:3", "Exodus 2:3", "Exodus 2:3-4"),
+					span(t, linkerBody, "שמות ב׳ ג׳", "Exodus 2:3"),
+				},
+				RefData: map[string]LinkedRef{
+					"Genesis 1:1": {HeRef: "בראשית א׳:א׳", PrimaryCategory: "Tanakh"},
+				},
+			},
+		})
+	})
+
+	res, err := client.Linker.FindRefs(context.Background(), linkerBody, &FindRefsOptions{
+		Title:    title,
+		WithText: true,
+	})
+	require.NoError(t, err)
+
+	want := []struct {
+		text      string
+		ref       string
+		ambiguous bool
+		language  string
+	}{
+		{"בראשית א׳ א׳", "Genesis 1:1", false, "he"},
+		{"Genesis 1:1", "Genesis 1:1", false, "en"},
+		{"Exodus 2:3", "", true, "en"},
+		{"שמות ב׳ ג׳", "Exodus 2:3", false, "he"},
+	}
+	require.Len(t, res.Body, len(want))
+	for i, w := range want {
+		got := res.Body[i]
+		assert.Equal(t, w.text, linkerBody[got.Start:got.End], "byte span of %q", w.text)
+		assert.Equal(t, w.text, got.Text)
+		assert.Equal(t, w.ref, got.Ref)
+		assert.Equal(t, w.ambiguous, got.Ambiguous)
+		assert.Equal(t, w.language, got.Language)
+		if w.ambiguous {
+			assert.Nil(t, got.Parsed)
+			assert.Len(t, got.Candidates, 2)
+		} else {
+			require.NotNil(t, got.Parsed)
+			assert.Equal(t, w.ref, got.Parsed.String())
+		}
+	}
+
+	require.Len(t, res.Title, 1)
+	assert.Equal(t, "Genesis 1:1", title[res.Title[0].Start:res.Title[0].End])
+	assert.Equal(t, "Tanakh", res.RefData["Genesis 1:1"].PrimaryCategory)
+}
+
+func TestFindRefs_SkipsUnusableSpans(t *testing.T) {
+	chars := utf8.RuneCountInString(linkerBody)
+	client := newLinkerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, findRefsResponse{Body: &findRefsSection{Results: []findRefsSpan{
+			{StartChar: 0, EndChar: 5, LinkFailed: true, Refs: []string{"Genesis 1:1"}},
+			{StartChar: 0, EndChar: 5},
+			{StartChar: -1, EndChar: 5, Refs: []string{"Genesis 1:1"}},
+			{StartChar: 5, EndChar: chars + 1, Refs: []string{"Genesis 1:1"}},
+			{StartChar: 6, EndChar: 5, Refs: []string{"Genesis 1:1"}},
+			{StartChar: chars - 1, EndChar: chars, Refs: []string{"Genesis 1:1"}},
+		}}})
+	})
+
+	res, err := client.Linker.FindRefs(context.Background(), linkerBody, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Body, 1)
+	assert.Equal(t, ".", res.Body[0].Text)
+	assert.Equal(t, len(linkerBody), res.Body[0].End)
+}
+
+func TestFindRefs_Empty(t *testing.T) {
+	_, err := NewClient().Linker.FindRefs(context.Background(), "", nil)
+	assert.ErrorIs(t, err, ErrEmptyLinkerText)
+}
+
+func TestFindRefs_PollsAsyncTask(t *testing.T) {
+	var polls atomic.Int32
+	client := newLinkerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/find-refs":
+			writeJSON(t, w, findRefsResponse{TaskID: "task-1"})
+		case "/async/task-1":
+			if polls.Add(1) < 3 {
+				writeJSON(t, w, asyncTask{TaskID: "task-1", State: "PENDING"})
+				return
+			}
+			writeJSON(t, w, asyncTask{TaskID: "task-1", State: "SUCCESS", Ready: true, Result: findRefsResponse{
+				Body: &findRefsSection{Results: []findRefsSpan{
+					span(t, linkerBody, "Genesis 1:1", "Genesis 1:1"),
+				}},
+			}})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	res, err := client.Linker.FindRefs(context.Background(), linkerBody, &FindRefsOptions{
+		PollInterval: time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), polls.Load())
+	require.Len(t, res.Body, 1)
+	assert.Equal(t, "Genesis 1:1", linkerBody[res.Body[0].Start:res.Body[0].End])
+}
+
+func TestFindRefs_AsyncTaskFailure(t *testing.T) {
+	client := newLinkerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/find-refs" {
+			writeJSON(t, w, findRefsResponse{TaskID: "task-2"})
+			return
+		}
+		writeJSON(t, w, asyncTask{TaskID: "task-2", State: "FAILURE"})
+	})
+
+	_, err := client.Linker.FindRefs(context.Background(), linkerBody, &FindRefsOptions{
+		PollInterval: time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "linker task task-2 failed")
+}
+
+func TestFindRefs_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var polls atomic.Int32
+	client := newLinkerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/find-refs" {
+			writeJSON(t, w, findRefsResponse{TaskID: "task-3"})
+			return
+		}
+		if polls.Add(1) == 2 {
+			cancel()
+		}
+		writeJSON(t, w, asyncTask{TaskID: "task-3", State: "PENDING"})
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Linker.FindRefs(ctx, linkerBody, &FindRefsOptions{
+			PollInterval: time.Millisecond,
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("FindRefs did not stop when its context was cancelled")
+	}
+	assert.LessOrEqual(t, polls.Load(), int32(3))
+}
@@ -0,0 +1,19 @@
+// Package linkify rewrites HTML and Markdown documents so that citations of
+// texts become links to the Sefaria website.
+//
+// Citations are found by a Finder, which is either Sefaria's remote linker or
+// the offline detector from the citation package:
+//
+//	finder := linkify.Linker(client.Linker, nil)
+//	// or, without network access once titles are cached:
+//	finder := linkify.Detector(citation.NewDetector(catalog))
+//
+//	out, err := linkify.Markdown(ctx, post, finder, nil)
+//
+// Text that is already a link, and text inside code, is left untouched. Each
+// document is searched with a single call to the Finder, so the remote linker
+// is only asked once per document regardless of its size.
+//
+// Links can optionally carry a tooltip holding the text of the first segment
+// of the ref, see TextTooltips.
+package linkify
@@ -0,0 +1,91 @@
+package linkify
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// protected are the elements whose text is never linked, either because it
+// is already a link or because it is code.
+var protected = map[atom.Atom]bool{
+	atom.A:        true,
+	atom.Code:     true,
+	atom.Pre:      true,
+	atom.Kbd:      true,
+	atom.Samp:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Textarea: true,
+	atom.Title:    true,
+}
+
+// HTML rewrites an HTML document or fragment so that every citation found
+// in its text becomes a link. Everything other than the linked citations is
+// written back out byte for byte.
+func HTML(ctx context.Context, src string, finder Finder, opts *Options) (string, error) {
+	spans := htmlSpans(src)
+	links, err := findLinks(ctx, src, spans, finder, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return rewrite(src, links, func(text string, l link) string {
+		var b strings.Builder
+		b.WriteString(`<a href="`)
+		b.WriteString(html.EscapeString(l.url))
+		b.WriteString(`" class="sefaria-ref" data-ref="`)
+		b.WriteString(html.EscapeString(l.ref))
+		b.WriteString(`"`)
+		if l.tooltip != "" {
+			b.WriteString(` title="`)
+			b.WriteString(html.EscapeString(l.tooltip))
+			b.WriteString(`"`)
+		}
+		b.WriteString(`>`)
+		b.WriteString(text)
+		b.WriteString(`</a>`)
+		return b.String()
+	}), nil
+}
+
+// htmlSpans returns the text of the document outside of protected elements.
+func htmlSpans(src string) []span {
+	var spans []span
+	depth := make(map[atom.Atom]int)
+	inside := 0
+
+	z := xhtml.NewTokenizer(strings.NewReader(src))
+	offset := 0
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			return spans
+		}
+		raw := len(z.Raw())
+		start := offset
+		offset += raw
+
+		switch tt {
+		case xhtml.TextToken:
+			if inside == 0 {
+				spans = append(spans, span{start: start, end: offset})
+			}
+		case xhtml.StartTagToken:
+			name, _ := z.TagName()
+			if a := atom.Lookup(name); protected[a] {
+				depth[a]++
+				inside++
+			}
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			if a := atom.Lookup(name); protected[a] && depth[a] > 0 {
+				depth[a]--
+				inside--
+			}
+		}
+	}
+}
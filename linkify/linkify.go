@@ -0,0 +1,178 @@
+package linkify
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/citation"
+)
+
+// Finder finds citations in text.
+type Finder interface {
+	FindRefs(ctx context.Context, text string) ([]sefaria.FoundRef, error)
+}
+
+// FinderFunc adapts an ordinary function to the Finder interface.
+type FinderFunc func(ctx context.Context, text string) ([]sefaria.FoundRef, error)
+
+func (f FinderFunc) FindRefs(ctx context.Context, text string) ([]sefaria.FoundRef, error) {
+	return f(ctx, text)
+}
+
+// Linker finds citations with Sefaria's remote linker.
+func Linker(linker *sefaria.LinkerService, opts *sefaria.FindRefsOptions) Finder {
+	return FinderFunc(func(ctx context.Context, text string) ([]sefaria.FoundRef, error) {
+		result, err := linker.FindRefs(ctx, text, opts)
+		if err != nil {
+			return nil, err
+		}
+		return result.Body, nil
+	})
+}
+
+// Detector finds citations offline with a citation.Detector.
+func Detector(detector *citation.Detector) Finder {
+	return FinderFunc(func(ctx context.Context, text string) ([]sefaria.FoundRef, error) {
+		return detector.Find(text), nil
+	})
+}
+
+// TooltipFunc returns the tooltip for a link to the given ref. An empty
+// tooltip is omitted.
+type TooltipFunc func(ctx context.Context, ref string) (string, error)
+
+// TextTooltips uses the text of the first segment of each ref as its
+// tooltip, preferring English over Hebrew. Each ref is only fetched once.
+func TextTooltips(texts *sefaria.TextService) TooltipFunc {
+	cache := make(map[string]string)
+	return func(ctx context.Context, ref string) (string, error) {
+		if tooltip, ok := cache[ref]; ok {
+			return tooltip, nil
+		}
+
+		text, err := texts.Get(ctx, ref, &sefaria.TextOptions{Format: sefaria.FormatTextOnly})
+		if err != nil {
+			return "", err
+		}
+
+		var tooltip string
+		switch {
+		case len(text.Text) > 0 && text.Text[0] != "":
+			tooltip = text.Text[0]
+		case len(text.He) > 0:
+			tooltip = text.He[0]
+		}
+		cache[ref] = tooltip
+		return tooltip, nil
+	}
+}
+
+type Options struct {
+	// The site links point to. Defaults to https://www.sefaria.org.
+	BaseURL string
+
+	// Tooltip, if set, provides the tooltip for each link.
+	Tooltip TooltipFunc
+}
+
+// refURL returns the URL of the reader page for a ref, e.g.
+// https://www.sefaria.org/Genesis.1.1
+func (o *Options) refURL(ref string) string {
+	site := sefaria.DefaultSite
+	if o != nil && o.BaseURL != "" {
+		if s, err := sefaria.NewSite(o.BaseURL); err == nil {
+			site = s
+		}
+	}
+	return site.Reader(ref, nil).String()
+}
+
+// span is a byte range of a document whose text may be linked.
+type span struct {
+	start, end int
+}
+
+// link is a found ref and where it sits in the document.
+type link struct {
+	start, end int
+	ref        string
+	url        string
+	tooltip    string
+}
+
+// findLinks searches the given spans of src, all at once, and returns the
+// resulting links in document order. Citations that are ambiguous or that
+// cross from one span into another are dropped.
+func findLinks(ctx context.Context, src string, spans []span, finder Finder, opts *Options) ([]link, error) {
+	if len(spans) == 0 {
+		return nil, nil
+	}
+
+	// Join the spans with newlines so that text from separate elements is
+	// never read as a single citation, and remember where each one starts.
+	var b strings.Builder
+	starts := make([]int, len(spans))
+	for i, s := range spans {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		starts[i] = b.Len()
+		b.WriteString(src[s.start:s.end])
+	}
+
+	found, err := finder.FindRefs(ctx, b.String())
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(found, func(a, b sefaria.FoundRef) int {
+		return a.Start - b.Start
+	})
+
+	var links []link
+	i := 0
+	for _, f := range found {
+		if f.Ref == "" || f.Ambiguous {
+			continue
+		}
+		for i < len(spans)-1 && f.Start >= starts[i+1] {
+			i++
+		}
+		offset := spans[i].start - starts[i]
+		if f.End-starts[i] > spans[i].end-spans[i].start {
+			continue
+		}
+
+		l := link{
+			start: f.Start + offset,
+			end:   f.End + offset,
+			ref:   f.Ref,
+			url:   opts.refURL(f.Ref),
+		}
+		if opts != nil && opts.Tooltip != nil {
+			l.tooltip, err = opts.Tooltip(ctx, f.Ref)
+			if err != nil {
+				return nil, err
+			}
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+// rewrite replaces every link in src with the markup produced by format.
+func rewrite(src string, links []link, format func(text string, l link) string) string {
+	var b strings.Builder
+	last := 0
+	for _, l := range links {
+		if l.start < last {
+			continue
+		}
+		b.WriteString(src[last:l.start])
+		b.WriteString(format(src[l.start:l.end], l))
+		last = l.end
+	}
+	b.WriteString(src[last:])
+	return b.String()
+}
@@ -0,0 +1,158 @@
+package linkify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria/citation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFinder() Finder {
+	return Detector(citation.NewDetector(citation.NewCatalog(
+		citation.Entry{Title: "Genesis", Titles: []string{"Gen."}, HeTitles: []string{"בראשית"}},
+		citation.Entry{Title: "Berakhot", HeTitles: []string{"ברכות"}},
+		citation.Entry{Title: "Ha’azinu"},
+		citation.Entry{Title: "Rashi on Genesis", CollectiveTitle: "Rashi", BaseTitle: "Genesis"},
+	)))
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text",
+			input:    "<p>See Genesis 1:1.</p>",
+			expected: `<p>See <a href="https://www.sefaria.org/Genesis.1.1" class="sefaria-ref" data-ref="Genesis 1:1">Genesis 1:1</a>.</p>`,
+		},
+		{
+			name:     "commentary",
+			input:    "<p>Rashi on Gen. 1:1</p>",
+			expected: `<p><a href="https://www.sefaria.org/Rashi_on_Genesis.1.1" class="sefaria-ref" data-ref="Rashi on Genesis 1:1">Rashi on Gen. 1:1</a></p>`,
+		},
+		{
+			name:     "existing link",
+			input:    `<p><a href="/x">Genesis 1:1</a> and Genesis 2:3</p>`,
+			expected: `<p><a href="/x">Genesis 1:1</a> and <a href="https://www.sefaria.org/Genesis.2.3" class="sefaria-ref" data-ref="Genesis 2:3">Genesis 2:3</a></p>`,
+		},
+		{
+			name:     "code",
+			input:    "<pre><code>Genesis 1:1</code></pre><code>Genesis 1:2</code>",
+			expected: "<pre><code>Genesis 1:1</code></pre><code>Genesis 1:2</code>",
+		},
+		{
+			name:     "markup around the text is preserved",
+			input:    "<p class=\"x\">A &amp; B, <b>ברכות דף ב ע״א</b></p>",
+			expected: `<p class="x">A &amp; B, <b><a href="https://www.sefaria.org/Berakhot.2a" class="sefaria-ref" data-ref="Berakhot 2a">ברכות דף ב ע״א</a></b></p>`,
+		},
+		{
+			name:     "citation split across elements",
+			input:    "<p>Genesis <b>1:1</b></p>",
+			expected: "<p>Genesis <b>1:1</b></p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := HTML(context.Background(), tt.input, testFinder(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestHTML_Tooltip(t *testing.T) {
+	opts := &Options{
+		BaseURL: "https://example.org/",
+		Tooltip: func(ctx context.Context, ref string) (string, error) {
+			return `In the "beginning" <God> created`, nil
+		},
+	}
+
+	out, err := HTML(context.Background(), "Genesis 1:1", testFinder(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, `<a href="https://example.org/Genesis.1.1" class="sefaria-ref" data-ref="Genesis 1:1" title="In the &#34;beginning&#34; &lt;God&gt; created">Genesis 1:1</a>`, out)
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text",
+			input:    "See Genesis 1:1.\n",
+			expected: "See [Genesis 1:1](https://www.sefaria.org/Genesis.1.1).\n",
+		},
+		{
+			name:     "existing link",
+			input:    "[Genesis 1:1](https://example.org) and Genesis 1:2",
+			expected: "[Genesis 1:1](https://example.org) and [Genesis 1:2](https://www.sefaria.org/Genesis.1.2)",
+		},
+		{
+			name:     "reference link",
+			input:    "[Genesis 1:1][1]\n\n[1]: https://example.org/Genesis 1:1\n",
+			expected: "[Genesis 1:1][1]\n\n[1]: https://example.org/Genesis 1:1\n",
+		},
+		{
+			name:     "code span",
+			input:    "`Genesis 1:1` and ``Genesis ` 1:2``",
+			expected: "`Genesis 1:1` and ``Genesis ` 1:2``",
+		},
+		{
+			name:     "fenced code",
+			input:    "```\nGenesis 1:1\n```\nGenesis 1:2\n",
+			expected: "```\nGenesis 1:1\n```\n[Genesis 1:2](https://www.sefaria.org/Genesis.1.2)\n",
+		},
+		{
+			name:     "indented code",
+			input:    "text\n\n    Genesis 1:1\n\nGenesis 1:2",
+			expected: "text\n\n    Genesis 1:1\n\n[Genesis 1:2](https://www.sefaria.org/Genesis.1.2)",
+		},
+		{
+			name:     "inline html and autolinks",
+			input:    "<a href=\"/x\">Genesis 1:1</a> <https://x.org/Genesis 1:2> https://x.org/Genesis_1:3",
+			expected: "<a href=\"/x\">Genesis 1:1</a> <https://x.org/Genesis 1:2> https://x.org/Genesis_1:3",
+		},
+		{
+			name:     "hebrew citation",
+			input:    "- בראשית א:א",
+			expected: "- [בראשית א:א](https://www.sefaria.org/Genesis.1.1)",
+		},
+		{
+			name:     "title is url encoded",
+			input:    "Ha’azinu 1:1",
+			expected: "[Ha’azinu 1:1](https://www.sefaria.org/Ha%E2%80%99azinu.1.1)",
+		},
+		{
+			name:     "emphasis",
+			input:    "*Berakhot 2a*",
+			expected: "*[Berakhot 2a](https://www.sefaria.org/Berakhot.2a)*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Markdown(context.Background(), tt.input, testFinder(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestMarkdown_Tooltip(t *testing.T) {
+	opts := &Options{
+		Tooltip: func(ctx context.Context, ref string) (string, error) {
+			return `say "hi"`, nil
+		},
+	}
+
+	out, err := Markdown(context.Background(), "Genesis 1:1 (Rashi on Genesis 1:1)", testFinder(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, `[Genesis 1:1](https://www.sefaria.org/Genesis.1.1 "say \"hi\"") ([Rashi on Genesis 1:1](https://www.sefaria.org/Rashi_on_Genesis.1.1 "say \"hi\""))`, out)
+}
@@ -0,0 +1,239 @@
+package linkify
+
+import (
+	"context"
+	"strings"
+)
+
+// Markdown rewrites a Markdown document so that every citation found in its
+// text becomes an inline link. Code blocks, code spans, existing links,
+// autolinks, inline HTML and link reference definitions are left untouched.
+func Markdown(ctx context.Context, src string, finder Finder, opts *Options) (string, error) {
+	spans := markdownSpans(src)
+	links, err := findLinks(ctx, src, spans, finder, opts)
+	if err != nil {
+		return "", err
+	}
+
+	urlEscaper := strings.NewReplacer("(", "%28", ")", "%29")
+	titleEscaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+	return rewrite(src, links, func(text string, l link) string {
+		var b strings.Builder
+		b.WriteString("[")
+		b.WriteString(text)
+		b.WriteString("](")
+		b.WriteString(urlEscaper.Replace(l.url))
+		if l.tooltip != "" {
+			b.WriteString(` "`)
+			b.WriteString(titleEscaper.Replace(l.tooltip))
+			b.WriteString(`"`)
+		}
+		b.WriteString(")")
+		return b.String()
+	}), nil
+}
+
+// markdownSpans returns the ranges of the document that hold ordinary text.
+func markdownSpans(src string) []span {
+	var spans []span
+	add := func(start, end int) {
+		if start >= end {
+			return
+		}
+		if n := len(spans); n > 0 && spans[n-1].end == start {
+			spans[n-1].end = end
+			return
+		}
+		spans = append(spans, span{start: start, end: end})
+	}
+
+	var fence string
+	prevBlank, indented := true, false
+	for offset := 0; offset < len(src); {
+		end := strings.IndexByte(src[offset:], '\n')
+		if end < 0 {
+			end = len(src)
+		} else {
+			end += offset + 1
+		}
+		line := src[offset:end]
+		trimmed := strings.TrimLeft(line, " ")
+		content := strings.TrimRight(line, "\r\n")
+		indent := len(line) - len(trimmed)
+		isBlank := strings.TrimSpace(line) == ""
+		isIndented := !isBlank && (indent >= 4 || strings.HasPrefix(line, "\t"))
+
+		switch {
+		case fence != "":
+			closing := strings.TrimSpace(trimmed)
+			if indent < 4 && strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+				fence = ""
+			}
+		case indent < 4 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			for len(fence) < len(trimmed) && trimmed[len(fence)] == fence[0] {
+				fence = trimmed[:len(fence)+1]
+			}
+		case isIndented && (prevBlank || indented):
+			// An indented code block starts after a blank line and runs
+			// until the first line that is not indented.
+			indented = true
+		case indent < 4 && isReferenceDefinition(trimmed):
+		default:
+			if !isBlank {
+				indented = false
+			}
+			markdownInline(src, offset, offset+len(content), add)
+			add(offset+len(content), end)
+		}
+
+		prevBlank = isBlank
+		offset = end
+	}
+	return spans
+}
+
+// isReferenceDefinition reports whether a line defines a link reference, as
+// in "[sefaria]: https://www.sefaria.org".
+func isReferenceDefinition(line string) bool {
+	if !strings.HasPrefix(line, "[") {
+		return false
+	}
+	i := strings.Index(line, "]:")
+	return i > 1 && !strings.Contains(line[:i], "](")
+}
+
+// markdownInline calls add for every range of src[start:end] that lies
+// outside of code spans, links, autolinks and inline HTML.
+func markdownInline(src string, start, end int, add func(start, end int)) {
+	text := start
+	i := start
+	for i < end {
+		skip := 0
+		switch src[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			skip = codeSpan(src[i:end])
+		case '!', '[':
+			skip = inlineLink(src[i:end])
+		case '<':
+			skip = inlineHTML(src[i:end])
+		case 'h':
+			if strings.HasPrefix(src[i:end], "http://") || strings.HasPrefix(src[i:end], "https://") {
+				skip = strings.IndexAny(src[i:end], " \t")
+				if skip < 0 {
+					skip = end - i
+				}
+			}
+		}
+
+		if skip == 0 {
+			i++
+			continue
+		}
+		add(text, i)
+		i += skip
+		text = i
+	}
+	add(text, min(i, end))
+}
+
+// codeSpan returns the length of the code span at the start of s, or zero.
+func codeSpan(s string) int {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	ticks := s[:n]
+	for i := n; i < len(s); {
+		j := strings.Index(s[i:], ticks)
+		if j < 0 {
+			return 0
+		}
+		i += j
+		k := i
+		for k < len(s) && s[k] == '`' {
+			k++
+		}
+		if k-i == n {
+			return k
+		}
+		i = k
+	}
+	return 0
+}
+
+// inlineLink returns the length of the link or image at the start of s, or
+// zero. Both inline links, "[text](url)", and reference links,
+// "[text][ref]", are recognized.
+func inlineLink(s string) int {
+	i := 0
+	if s[0] == '!' {
+		if len(s) < 2 || s[1] != '[' {
+			return 0
+		}
+		i = 1
+	}
+
+	close := matching(s, i, '[', ']')
+	if close < 0 || close+1 >= len(s) {
+		return 0
+	}
+	switch s[close+1] {
+	case '(':
+		if end := matching(s, close+1, '(', ')'); end >= 0 {
+			return end + 1
+		}
+	case '[':
+		if end := matching(s, close+1, '[', ']'); end >= 0 {
+			return end + 1
+		}
+	}
+	return 0
+}
+
+// matching returns the index of the bracket that closes the one at s[start].
+func matching(s string, start int, open, close byte) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// inlineHTML returns the length of the autolink or HTML tag at the start of
+// s, or zero. An opening anchor tag extends to its closing tag so that the
+// text of existing links is left alone.
+func inlineHTML(s string) int {
+	if len(s) < 2 || !(s[1] == '/' || s[1] == '!' || isASCIILetter(s[1])) {
+		return 0
+	}
+	end := strings.IndexByte(s, '>')
+	if end < 0 {
+		return 0
+	}
+
+	tag := strings.ToLower(s[:end])
+	if tag == "<a" || strings.HasPrefix(tag, "<a ") {
+		if close := strings.Index(strings.ToLower(s), "</a>"); close >= 0 {
+			return close + len("</a>")
+		}
+	}
+	return end + 1
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
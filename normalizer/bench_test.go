@@ -0,0 +1,140 @@
+package normalizer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ryanfaerman/go-sefaria"
+	"github.com/ryanfaerman/go-sefaria/normalizer"
+)
+
+var benchmarkNormalizers = []normalizer.Normalizer{
+	normalizer.HTMLUnescape,
+	normalizer.UnicodeNFC,
+	normalizer.Punctuation,
+}
+
+// tractate builds a text about the size of a tractate of the Talmud, with
+// the entities and punctuation that normalizers typically rewrite.
+func tractate(segments int) *sefaria.Text {
+	t := &sefaria.Text{
+		Ref:          "Berakhot 2a-64a",
+		HeRef:        "ברכות ב׳ א-ס״ד א",
+		SectionNames: []string{"Daf", "Line"},
+		Categories:   []string{"Talmud", "Bavli", "Seder Zeraim"},
+		Versions:     make([]sefaria.Version, 4),
+	}
+	for i := range segments {
+		t.Text = append(t.Text, fmt.Sprintf("<b>MISHNA:</b> From when does one recite &ldquo;Shema&rdquo; in the evening&hellip; line %d", i))
+		t.He = append(t.He, fmt.Sprintf("מֵאֵימָתַי קוֹרִין אֶת שְׁמַע בְּעַרְבִית&nbsp;— %d", i))
+	}
+	for i := range t.Versions {
+		t.Versions[i] = sefaria.Version{
+			VersionTitle:  fmt.Sprintf("William Davidson Edition &mdash; %d", i),
+			VersionSource: "https://www.korenpub.com/koren_en_usd/koren/talmud/koren-talmud-bavli-no.html",
+			License:       "CC-BY-NC",
+			VersionNotes:  "Notes &amp; &ldquo;more&rdquo;",
+		}
+	}
+	return t
+}
+
+// index builds an index record with a deeply nested schema, as decoded from
+// JSON.
+func index(nodes int) sefaria.Index {
+	schema := map[string]any{"nodeType": "SchemaNode", "titles": []any{}}
+	var children []any
+	for i := range nodes {
+		children = append(children, map[string]any{
+			"nodeType": "JaggedArrayNode",
+			"depth":    2,
+			"titles": []any{
+				map[string]any{"lang": "en", "text": fmt.Sprintf("Chapter &ldquo;%d&rdquo;", i), "primary": true},
+				map[string]any{"lang": "he", "text": fmt.Sprintf("פרק %d", i)},
+			},
+			"addressTypes": []any{"Integer", "Integer"},
+			"sectionNames": []any{"Chapter", "Paragraph"},
+		})
+	}
+	schema["nodes"] = children
+
+	data, err := json.Marshal(map[string]any{
+		"title":         "Mishneh Torah, Prayer and the Priestly Blessing",
+		"categories":    []any{"Halakhah", "Mishneh Torah"},
+		"schema":        schema,
+		"titleVariants": []any{"Rambam &amp; Tefillah"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	var out sefaria.Index
+	if err := json.Unmarshal(data, &out); err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func TestApply_Text(t *testing.T) {
+	text := tractate(2)
+	normalizer.Apply(text, benchmarkNormalizers...)
+
+	if want := "<b>MISHNA:</b> From when does one recite \"Shema\" in the evening... line 0"; text.Text[0] != want {
+		t.Errorf("Text[0] = %q, want %q", text.Text[0], want)
+	}
+	if want := "מֵאֵימָתַי קוֹרִין אֶת שְׁמַע בְּעַרְבִית - 0"; text.He[0] != want {
+		t.Errorf("He[0] = %q, want %q", text.He[0], want)
+	}
+	if want := tractate(2).Versions[0].VersionSource; text.Versions[0].VersionSource != want {
+		t.Errorf("VersionSource = %q, want %q", text.Versions[0].VersionSource, want)
+	}
+}
+
+func TestApply_Index(t *testing.T) {
+	idx := index(2)
+	normalizer.Apply(&idx, benchmarkNormalizers...)
+
+	variants := idx["titleVariants"].([]any)
+	if !reflect.DeepEqual(variants, []any{"Rambam & Tefillah"}) {
+		t.Errorf("titleVariants = %q", variants)
+	}
+}
+
+func BenchmarkApply_Text(b *testing.B) {
+	for _, size := range []int{100, 10000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			// Apply rewrites the text in place, so each run is given a
+			// fresh one, built off the clock.
+			b.ReportAllocs()
+			for range b.N {
+				b.StopTimer()
+				text := tractate(size)
+				b.StartTimer()
+				normalizer.Apply(text, benchmarkNormalizers...)
+			}
+		})
+	}
+}
+
+func BenchmarkApply_Index(b *testing.B) {
+	for _, size := range []int{10, 1000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				b.StopTimer()
+				idx := index(size)
+				b.StartTimer()
+				normalizer.Apply(&idx, benchmarkNormalizers...)
+			}
+		})
+	}
+}
+
+func BenchmarkPunctuation(b *testing.B) {
+	s := "From when does one recite “Shema” in the evening… — line"
+	b.ReportAllocs()
+	for range b.N {
+		normalizer.Punctuation(s)
+	}
+}
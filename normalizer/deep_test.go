@@ -0,0 +1,148 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// deepJSON resembles the untyped payloads of the index, topic, related and
+// lexicon endpoints, with strings that need normalizing at every depth.
+const deepJSON = `{
+	"title": "Rashi &amp; Ramban",
+	"schema": {
+		"titles": [
+			{"lang": "en", "text": "Genesis &ldquo;Bereshit&rdquo;", "primary": true},
+			{"lang": "he", "text": "בראשית&nbsp;א"}
+		],
+		"nodes": [
+			{
+				"sectionNames": ["Chapter", "Verse &amp; Line"],
+				"nodes": [
+					{"titles": [[["deep &amp; deeper"], "and &hellip;"]]}
+				]
+			}
+		]
+	},
+	"links": [
+		{"ref": "Genesis 1:1", "text": ["In the beginning &mdash;", ["nested &amp; array"]]},
+		{"collectiveTitle": {"en": "Rashi &amp; Co", "he": "רש&quot;י"}}
+	],
+	"content": {"senses": [{"definition": "to &lsquo;create&rsquo;", "senses": [{"definition": "deep &amp; sense"}]}]},
+	"count": 3,
+	"empty": null,
+	"flags": [true, false, null]
+}`
+
+var entity = regexp.MustCompile(`&\w+;`)
+
+// remaining returns every string in v that still holds something the
+// normalizers should have removed.
+func remaining(v any) []string {
+	var out []string
+	switch v := v.(type) {
+	case string:
+		if entity.MatchString(v) || strings.ContainsAny(v, "“”‘’—…\u00A0") {
+			out = append(out, v)
+		}
+	case []any:
+		for _, e := range v {
+			out = append(out, remaining(e)...)
+		}
+	case map[string]any:
+		for _, e := range v {
+			out = append(out, remaining(e)...)
+		}
+	}
+	return out
+}
+
+func TestApply_DeepJSON(t *testing.T) {
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(deepJSON), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	Apply(&payload, HTMLUnescape, Punctuation)
+
+	if left := remaining(payload); len(left) > 0 {
+		t.Errorf("strings left unnormalized: %q", left)
+	}
+
+	nested := payload["schema"].(map[string]any)["nodes"].([]any)[0].(map[string]any)["nodes"].([]any)[0].(map[string]any)["titles"]
+	expected := []any{[]any{[]any{"deep & deeper"}, "and ..."}}
+	if !reflect.DeepEqual(nested, expected) {
+		t.Errorf("nested titles = %#v, want %#v", nested, expected)
+	}
+	if payload["count"] != 3.0 || payload["empty"] != nil {
+		t.Errorf("non-string values changed: %v, %v", payload["count"], payload["empty"])
+	}
+}
+
+func TestApply_DeepNamedMap(t *testing.T) {
+	// Index, Topic and RelatedContent are all named map types.
+	type Payload map[string]any
+
+	var payload Payload
+	if err := json.Unmarshal([]byte(deepJSON), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	// The map is passed by value, as it would be when normalizing a field.
+	Apply(payload, HTMLUnescape, Punctuation)
+
+	if left := remaining(map[string]any(payload)); len(left) > 0 {
+		t.Errorf("strings left unnormalized: %q", left)
+	}
+}
+
+func TestApply_MapOfStructs(t *testing.T) {
+	type Entry struct {
+		Text    string
+		Ref     string `normalize:"-"`
+		Aliases []string
+		Pair    [2]string
+	}
+
+	entries := map[string]Entry{
+		"a": {Text: "x &amp; y", Ref: "a &amp; b", Aliases: []string{"c &amp; d"}, Pair: [2]string{"e &amp; f", "g"}},
+	}
+	Apply(&entries, HTMLUnescape)
+
+	expected := Entry{Text: "x & y", Ref: "a &amp; b", Aliases: []string{"c & d"}, Pair: [2]string{"e & f", "g"}}
+	if !reflect.DeepEqual(entries["a"], expected) {
+		t.Errorf("entries[a] = %+v, want %+v", entries["a"], expected)
+	}
+}
+
+func TestApply_InterfaceHoldingStruct(t *testing.T) {
+	type Sense struct {
+		Definition string
+	}
+
+	values := []any{Sense{Definition: "to &lsquo;create&rsquo;"}, [1]string{"&amp;"}}
+	Apply(&values, HTMLUnescape, Punctuation)
+
+	if values[0].(Sense).Definition != "to 'create'" {
+		t.Errorf("values[0] = %+v", values[0])
+	}
+	if values[1].([1]string)[0] != "&" {
+		t.Errorf("values[1] = %+v", values[1])
+	}
+}
+
+func TestApply_DeepCopyLeavesSharedValues(t *testing.T) {
+	shared := []any{"a &amp; b"}
+	payload := map[string]any{"list": shared}
+
+	Apply(&payload, HTMLUnescape)
+
+	if payload["list"].([]any)[0] != "a & b" {
+		t.Errorf("payload[list] = %v", payload["list"])
+	}
+	if shared[0] != "a &amp; b" {
+		t.Errorf("shared slice was modified: %v", shared)
+	}
+}
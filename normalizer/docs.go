@@ -18,6 +18,20 @@
 //   - HTMLUnescape: Converts HTML entities to their corresponding characters
 //   - UnicodeNFC: Normalizes Unicode text to NFC (Canonical Decomposed, then Canonical Composed) form
 //   - Punctuation: Replaces fancy/smart punctuation with standard ASCII equivalents
+//   - StripTags: Removes HTML tags and Sefaria's footnotes, leaving plain text
+//   - SanitizeHTML: Builds a normalizer that keeps only a whitelist of tags
+//
+// For Hebrew text there are also:
+//
+//   - StripNiqqud: Removes vowel points
+//   - StripCantillation: Removes te'amim (cantillation marks)
+//   - FoldFinalLetters: Replaces final letters with their ordinary forms
+//   - HebrewPunctuation: Replaces the maqaf and sof pasuq with ASCII equivalents
+//   - StripParashaMarkers: Removes Sefaria's {פ} and {ס} paragraph markers
+//   - CollapseKtivQere: Keeps only the qere of each ktiv and qere pair
+//
+// Each of these only touches Hebrew characters, so they are safe to add to
+// Client.Normalizers for responses that mix Hebrew and English.
 //
 // # Usage Example
 //
@@ -60,6 +74,22 @@
 //		normalizer.HTMLUnescape,
 //	}
 //
+// # Struct Tags
+//
+// Individual struct fields can opt out of normalization, or choose their own
+// normalizers by name, with the normalize struct tag:
+//
+//	type Source struct {
+//		Text string                            // normalizers passed to Apply
+//		Ref  string `normalize:"-"`            // never normalized
+//		Note string `normalize:"html,nfc"`     // HTMLUnescape, then UnicodeNFC
+//	}
+//
+// The built-in normalizers are registered as html, nfc, punctuation,
+// strip-tags, niqqud, cantillation, final-letters, hebrew-punctuation,
+// parasha-markers and ktiv-qere, and SanitizeHTML with DefaultAllowedTags as
+// sanitize. Others can be added with Register.
+//
 // # Supported Data Types
 //
 // The Apply function works with the following data types:
@@ -69,13 +99,18 @@
 //   - Pointers: Dereferences and applies to the underlying value
 //   - Interfaces: Applies to the underlying concrete value
 //   - Slices/Arrays: Applies to each element
-//   - Maps: Applies to string values and recursively to other values
+//   - Maps: Replaces each value with a normalized copy, since map values cannot
+//     be modified in place. Nested maps and slices, as found in decoded JSON,
+//     are copied all the way down
 //
 // # Performance Considerations
 //
-// The Apply function uses reflection to traverse data structures, which has some
-// performance overhead. For high-performance scenarios, consider applying
-// normalizers directly to known string fields rather than using the generic Apply function.
+// The Apply function uses reflection to traverse data structures. The first
+// time it sees a type it compiles a plan of where that type can hold strings,
+// which is cached and reused, so fields that can never hold a string, such as
+// slices of numbers, are skipped without being visited. For high-performance
+// scenarios, consider applying normalizers directly to known string fields
+// rather than using the generic Apply function.
 //
 // # Thread Safety
 //
@@ -0,0 +1,140 @@
+package normalizer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// StripNiqqud removes Hebrew vowel points, including the dagesh, rafe and
+// the dots distinguishing shin from sin. Cantillation marks are left in
+// place; see StripCantillation.
+//
+// Example:
+//
+//	StripNiqqud("בְּרֵאשִׁית") // Returns "בראשית"
+func StripNiqqud(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isNiqqud(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func isNiqqud(r rune) bool {
+	return (r >= '\u05B0' && r <= '\u05BC') || r == '\u05BF' || r == '\u05C1' || r == '\u05C2' || r == '\u05C7'
+}
+
+// StripCantillation removes the te'amim, the cantillation marks of the
+// Hebrew Bible, along with the meteg and the upper and lower puncta. Vowel
+// points are left in place; see StripNiqqud.
+//
+// Example:
+//
+//	StripCantillation("בְּרֵאשִׁ֖ית") // Returns "בְּרֵאשִׁית"
+func StripCantillation(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isCantillation(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func isCantillation(r rune) bool {
+	return (r >= '\u0591' && r <= '\u05AF') || r == '\u05BD' || r == '\u05C4' || r == '\u05C5'
+}
+
+var finalLetters = strings.NewReplacer("ך", "כ", "ם", "מ", "ן", "נ", "ף", "פ", "ץ", "צ")
+
+// FoldFinalLetters replaces the final forms of Hebrew letters with their
+// ordinary forms, which is useful when comparing or indexing words that may
+// be cut off or joined differently.
+//
+// Example:
+//
+//	FoldFinalLetters("שלום") // Returns "שלומ"
+func FoldFinalLetters(s string) string {
+	return finalLetters.Replace(s)
+}
+
+var hebrewPunctuation = strings.NewReplacer("־", "-", "׃", ":")
+
+// HebrewPunctuation replaces the Hebrew maqaf with a hyphen and the sof
+// pasuq with a colon, for fonts and terminals that lack them.
+//
+// Example:
+//
+//	HebrewPunctuation("עַל־פְּנֵי הַמָּיִם׃") // Returns "עַל-פְּנֵי הַמָּיִם:"
+func HebrewPunctuation(s string) string {
+	return hebrewPunctuation.Replace(s)
+}
+
+var parashaMarker = regexp.MustCompile(`[ \t\x{00A0}]*\{[פס]\}[ \t\x{00A0}]*`)
+
+// StripParashaMarkers removes the {פ} and {ס} markers Sefaria uses for open
+// and closed paragraph breaks in the Torah. Text on either side of a marker
+// is kept apart by a single space.
+//
+// Example:
+//
+//	StripParashaMarkers("יוֹם הַשִּׁשִּׁי׃ {פ}") // Returns "יוֹם הַשִּׁשִּׁי׃"
+func StripParashaMarkers(s string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range parashaMarker.FindAllStringIndex(s, -1) {
+		b.WriteString(s[last:m[0]])
+		if m[0] > 0 && m[1] < len(s) && !isLineBreak(s[m[0]-1]) && !isLineBreak(s[m[1]]) {
+			b.WriteByte(' ')
+		}
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isLineBreak(c byte) bool {
+	return c == '\n' || c == '\r'
+}
+
+var (
+	ktivQereHTML = regexp.MustCompile(`<span class="mam-kq">\s*<span class="mam-kq-k">[^<]*</span>\s*<span class="mam-kq-q">\[([^<\]]*)\]</span>\s*</span>`)
+	ktivQereText = regexp.MustCompile(`\(([^()\[\]]+)\)\s*\[([^()\[\]]+)\]`)
+)
+
+// CollapseKtivQere replaces each ktiv and qere pair, the written and read
+// forms of a word, with just the qere. Sefaria writes the pair as the ktiv in
+// parentheses followed by the qere in brackets, as in "(ktiv) [qere]", and
+// may wrap it in spans. Only pairs where both forms are Hebrew are collapsed,
+// so English asides are left alone.
+//
+// Example:
+//
+//	CollapseKtivQere("(הוצא) [הַיְצֵא]") // Returns "הַיְצֵא"
+func CollapseKtivQere(s string) string {
+	if !strings.Contains(s, "[") {
+		return s
+	}
+	s = ktivQereHTML.ReplaceAllString(s, "$1")
+	return ktivQereText.ReplaceAllStringFunc(s, func(m string) string {
+		parts := ktivQereText.FindStringSubmatch(m)
+		if !containsHebrew(parts[1]) || !containsHebrew(parts[2]) {
+			return m
+		}
+		return parts[2]
+	})
+}
+
+func containsHebrew(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package normalizer
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The opening words of Genesis, with and without niqqud and te'amim.
+const (
+	bereshitFull      = "בְּרֵאשִׁ֖ית בָּרָ֣א אֱלֹהִ֑ים"
+	bereshitNiqqud    = "בְּרֵאשִׁית בָּרָא אֱלֹהִים"
+	bereshitConsonant = "בראשית ברא אלהים"
+)
+
+func TestStripNiqqud(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"vowels and dagesh", "בְּרֵאשִׁית", "בראשית"},
+		{"keeps cantillation", "בָּרָ֣א", "בר֣א"},
+		{"shin and sin dots", "שָׂשׂ וְשָׁשׁ", "שש ושש"},
+		{"qamats qatan and rafe", "כׇּל בֿ", "כל ב"},
+		{"mixed with english", "Genesis 1:1 בְּרֵאשִׁית, \"In the beginning\"", "Genesis 1:1 בראשית, \"In the beginning\""},
+		{"no hebrew", "café naïve", "café naïve"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StripNiqqud(tt.input)
+			if result != tt.expected {
+				t.Errorf("StripNiqqud(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStripCantillation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"te'amim", bereshitFull, bereshitNiqqud},
+		{"meteg", "הָֽאָרֶץ", "הָאָרֶץ"},
+		{"puncta", "לׄוׄ", "לו"},
+		{"keeps maqaf and sof pasuq", "עַֽל־פְּנֵ֥י הַמָּֽיִם׃", "עַל־פְּנֵי הַמָּיִם׃"},
+		{"mixed with english", "Rashi on בְּרֵאשִׁ֖ית", "Rashi on בְּרֵאשִׁית"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StripCantillation(tt.input)
+			if result != tt.expected {
+				t.Errorf("StripCantillation(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFoldFinalLetters(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"all final letters", "ךםןףץ", "כמנפצ"},
+		{"word", "שלום", "שלומ"},
+		{"ordinary letters unchanged", "כמנפצ", "כמנפצ"},
+		{"mixed with english", "Shalom שלום", "Shalom שלומ"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FoldFinalLetters(tt.input)
+			if result != tt.expected {
+				t.Errorf("FoldFinalLetters(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHebrewPunctuation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"maqaf", "עַל־פְּנֵי", "עַל-פְּנֵי"},
+		{"sof pasuq", "וְהָאָרֶץ׃", "וְהָאָרֶץ:"},
+		{"both", "עַל־פְּנֵי הַמָּיִם׃", "עַל-פְּנֵי הַמָּיִם:"},
+		{"ascii unchanged", "well-known: yes", "well-known: yes"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HebrewPunctuation(tt.input)
+			if result != tt.expected {
+				t.Errorf("HebrewPunctuation(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStripParashaMarkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"trailing petucha", "יוֹם הַשִּׁשִּׁי׃ {פ}", "יוֹם הַשִּׁשִּׁי׃"},
+		{"setuma between words", "לְמִינֵהוּ׃ {ס} וַיֹּאמֶר", "לְמִינֵהוּ׃ וַיֹּאמֶר"},
+		{"leading marker", "{פ} וַיְכֻלּוּ", "וַיְכֻלּוּ"},
+		{"before line break", "הַשִּׁשִּׁי׃ {פ}\nוַיְכֻלּוּ", "הַשִּׁשִּׁי׃\nוַיְכֻלּוּ"},
+		{"with html", "הַשִּׁשִּׁי׃ {פ}<br>וַיְכֻלּוּ", "הַשִּׁשִּׁי׃ <br>וַיְכֻלּוּ"},
+		{"other braces unchanged", "see {note} and {א}", "see {note} and {א}"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StripParashaMarkers(tt.input)
+			if result != tt.expected {
+				t.Errorf("StripParashaMarkers(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollapseKtivQere(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "(הוצא) [הַיְצֵא] אִתָּךְ", "הַיְצֵא אִתָּךְ"},
+		{"no space", "(הוצא)[הַיְצֵא]", "הַיְצֵא"},
+		{
+			"html",
+			`<span class="mam-kq"><span class="mam-kq-k">(הוצא)</span> <span class="mam-kq-q">[הַיְצֵא]</span></span> אִתָּךְ`,
+			"הַיְצֵא אִתָּךְ",
+		},
+		{"several", "(א) [ב] ו(ג) [ד]", "ב וד"},
+		{"english left alone", "the text (sic) [emphasis added]", "the text (sic) [emphasis added]"},
+		{"parentheses only", "(הוצא) and more", "(הוצא) and more"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CollapseKtivQere(tt.input)
+			if result != tt.expected {
+				t.Errorf("CollapseKtivQere(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHebrewNormalizers_Composed(t *testing.T) {
+	type Verse struct {
+		Ref  string
+		He   []string
+		Text []string
+	}
+
+	v := &Verse{
+		Ref: "Genesis 1:1",
+		He: []string{
+			bereshitFull + "׃ {פ}",
+			"עַֽל־פְּנֵ֥י (הוצא) [הַיְצֵ֣א]",
+		},
+		Text: []string{"In the beginning God created"},
+	}
+
+	Apply(v, StripParashaMarkers, CollapseKtivQere, StripCantillation, StripNiqqud, HebrewPunctuation, FoldFinalLetters)
+
+	expected := []string{"בראשית ברא אלהימ:", "על-פני היצא"}
+	for i, want := range expected {
+		if v.He[i] != want {
+			t.Errorf("He[%d] = %q, want %q", i, v.He[i], want)
+		}
+	}
+	if v.Text[0] != "In the beginning God created" || v.Ref != "Genesis 1:1" {
+		t.Errorf("English text changed: %q, %q", v.Ref, v.Text[0])
+	}
+}
+
+// ExampleStripNiqqud demonstrates reducing pointed text to its consonants
+func ExampleStripNiqqud() {
+	fmt.Println(StripNiqqud(StripCantillation(bereshitFull)) == bereshitConsonant)
+	// Output: true
+}
+
+// ExampleCollapseKtivQere demonstrates keeping only the qere of a word
+func ExampleCollapseKtivQere() {
+	fmt.Println(CollapseKtivQere("(הוצא) [הַיְצֵא] אִתָּךְ"))
+	// Output: הַיְצֵא אִתָּךְ
+}
@@ -1,6 +1,12 @@
 package normalizer
 
-import "html"
+import (
+	"html"
+	"slices"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
 
 // HTMLUnescape converts HTML entities to their corresponding characters.
 // This function uses the standard library's html.UnescapeString to decode
@@ -13,3 +19,137 @@ import "html"
 func HTMLUnescape(s string) string {
 	return html.UnescapeString(s)
 }
+
+// StripTags removes every HTML tag, keeping the text between them. Line
+// breaks (<br>) become newlines, entities are decoded, and the contents of
+// <script> and <style> are dropped.
+//
+// Sefaria's footnotes, a <sup class="footnote-marker"> followed by an
+// <i class="footnote">, are removed entirely, marker and note alike, since
+// they are not part of the running text. Use sefaria.ParseSegment to keep
+// them.
+//
+// Example:
+//
+//	StripTags("<b>In the beginning</b> God created<br>the heaven") // Returns "In the beginning God created\nthe heaven"
+func StripTags(s string) string {
+	if !strings.ContainsRune(s, '<') {
+		return html.UnescapeString(s)
+	}
+	return sanitize(s, nil)
+}
+
+// DefaultAllowedTags are the tags kept by the "sanitize" struct tag: the
+// inline formatting found in Sefaria's texts.
+var DefaultAllowedTags = []string{"b", "strong", "i", "em", "u", "small", "big", "sup", "sub", "span", "br"}
+
+// SanitizeHTML returns a normalizer that keeps only the allowed tags and
+// strips all others, keeping their text. Only the class and dir attributes of
+// allowed tags are kept, so the result is safe to embed in a page. Text,
+// including its entities, is kept as written.
+//
+// As with StripTags, footnotes are removed unless both <sup> and <i> are
+// allowed, in which case they are kept along with their classes.
+//
+// Example:
+//
+//	SanitizeHTML("b")(`<b onclick="x()">bold</b> <a href="#">link</a>`) // Returns "<b>bold</b> link"
+func SanitizeHTML(allowed ...string) Normalizer {
+	allowed = slices.Clone(allowed)
+	return func(s string) string {
+		if !strings.ContainsRune(s, '<') {
+			return s
+		}
+		return sanitize(s, allowed)
+	}
+}
+
+// escapeBrackets escapes stray angle brackets in text, which is otherwise
+// kept exactly as written.
+var escapeBrackets = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+// sanitize keeps the allowed tags of s. A nil list of allowed tags strips
+// every tag and returns plain text rather than HTML.
+func sanitize(s string, allowed []string) string {
+	plain := allowed == nil
+	keepFootnotes := slices.Contains(allowed, "sup") && slices.Contains(allowed, "i")
+
+	var b strings.Builder
+	z := nethtml.NewTokenizer(strings.NewReader(s))
+
+	// skip counts the open elements whose contents are being dropped.
+	var skip []string
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			return b.String()
+		}
+		raw := string(z.Raw())
+		tok := z.Token()
+
+		if len(skip) > 0 {
+			switch tt {
+			case nethtml.StartTagToken:
+				if tok.Data == skip[len(skip)-1] {
+					skip = append(skip, tok.Data)
+				}
+			case nethtml.EndTagToken:
+				if tok.Data == skip[len(skip)-1] {
+					skip = skip[:len(skip)-1]
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case nethtml.TextToken:
+			if plain {
+				b.WriteString(tok.Data)
+			} else {
+				b.WriteString(escapeBrackets.Replace(raw))
+			}
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if tok.Data == "script" || tok.Data == "style" || (!keepFootnotes && isFootnote(tok)) {
+				if tt == nethtml.StartTagToken {
+					skip = append(skip, tok.Data)
+				}
+				continue
+			}
+			switch {
+			case tok.Data == "br" && (plain || !slices.Contains(allowed, "br")):
+				b.WriteByte('\n')
+			case slices.Contains(allowed, tok.Data):
+				tok.Attr = slices.DeleteFunc(tok.Attr, func(a nethtml.Attribute) bool {
+					return a.Namespace != "" || (a.Key != "class" && a.Key != "dir")
+				})
+				b.WriteString(tok.String())
+			}
+		case nethtml.EndTagToken:
+			if slices.Contains(allowed, tok.Data) {
+				b.WriteString(tok.String())
+			}
+		}
+	}
+}
+
+// isFootnote reports whether tok starts either part of a Sefaria footnote:
+// the <sup class="footnote-marker"> or the <i class="footnote"> holding its
+// text.
+func isFootnote(tok nethtml.Token) bool {
+	switch tok.Data {
+	case "sup":
+		return hasClass(tok, "footnote-marker")
+	case "i":
+		return hasClass(tok, "footnote")
+	}
+	return false
+}
+
+func hasClass(tok nethtml.Token, class string) bool {
+	for _, a := range tok.Attr {
+		if a.Key == "class" && slices.Contains(strings.Fields(a.Val), class) {
+			return true
+		}
+	}
+	return false
+}
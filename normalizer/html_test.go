@@ -148,3 +148,96 @@ func ExampleHTMLUnescape_advanced() {
 	fmt.Println(normalized)
 	// Output: He said "Hello & goodbye" < 5 minutes
 }
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"formatting", "<b>In the beginning</b> God <i>created</i>", "In the beginning God created"},
+		{"line breaks", "the heaven<br>and the earth<br/>", "the heaven\nand the earth\n"},
+		{"entities", "Rashi &amp; <b>Ramban</b> &ldquo;say&rdquo;", "Rashi & Ramban “say”"},
+		{"entities without tags", "Rashi &amp; Ramban", "Rashi & Ramban"},
+		{
+			"footnotes",
+			`And God said<sup class="footnote-marker">1</sup><i class="footnote">Or <i>spoke</i>.</i>, let there be light`,
+			"And God said, let there be light",
+		},
+		{"script and style", "a<script>alert(1)</script><style>b{}</style>b", "ab"},
+		{"hebrew", "<b>בְּרֵאשִׁית</b> בָּרָא", "בְּרֵאשִׁית בָּרָא"},
+		{"no html", "plain text", "plain text"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StripTags(tt.input)
+			if result != tt.expected {
+				t.Errorf("StripTags(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		input    string
+		expected string
+	}{
+		{"keeps allowed", []string{"b"}, "<b>bold</b> <u>under</u>", "<b>bold</b> under"},
+		{"drops attributes", []string{"b", "span"}, `<b onclick="x()">a</b><span class="x" dir="rtl" style="y">b</span>`, `<b>a</b><span class="x" dir="rtl">b</span>`},
+		{"drops links", []string{"b"}, `<a href="javascript:x()">link</a>`, "link"},
+		{"drops scripts", []string{"b"}, "a<script>alert(1)</script>", "a"},
+		{"keeps entities", []string{"b"}, "<b>a &amp; b</b>", "<b>a &amp; b</b>"},
+		{"line breaks", []string{"b"}, "a<br>b", "a\nb"},
+		{"allowed line breaks", []string{"br"}, "a<br>b", "a<br>b"},
+		{
+			"footnotes removed",
+			[]string{"b", "i"},
+			`said<sup class="footnote-marker">1</sup><i class="footnote">note</i> <i>then</i>`,
+			"said <i>then</i>",
+		},
+		{
+			"footnotes kept",
+			[]string{"sup", "i"},
+			`said<sup class="footnote-marker">1</sup><i class="footnote">note</i>`,
+			`said<sup class="footnote-marker">1</sup><i class="footnote">note</i>`,
+		},
+		{"no html", []string{"b"}, "a & b", "a & b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SanitizeHTML(tt.allowed...)(tt.input)
+			if result != tt.expected {
+				t.Errorf("SanitizeHTML(%q)(%q) = %q, want %q", tt.allowed, tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApply_StripTagsTag(t *testing.T) {
+	type Segment struct {
+		Text string `normalize:"strip-tags"`
+		HTML string `normalize:"sanitize"`
+	}
+
+	s := &Segment{
+		Text: `<b>a</b><sup class="footnote-marker">*</sup><i class="footnote">n</i>`,
+		HTML: `<b>a</b><a href="x">b</a>`,
+	}
+	Apply(s, HTMLUnescape)
+
+	if s.Text != "a" || s.HTML != "<b>a</b>b" {
+		t.Errorf("Apply() = %+v", s)
+	}
+}
+
+// ExampleStripTags demonstrates reducing a segment to plain text
+func ExampleStripTags() {
+	fmt.Println(StripTags(`<b>In the beginning</b> God created<sup class="footnote-marker">1</sup><i class="footnote">Or: When God began to create</i>`))
+	// Output: In the beginning God created
+}
@@ -13,7 +13,8 @@ type Normalizer func(string) string
 // to string fields found within structs, slices, arrays, maps, and pointers.
 //
 // Apply modifies the input value in-place. If the value is nil, the function returns early.
-// For maps, only string values are normalized directly; other value types are processed recursively.
+// Map values cannot be modified in place, so each is replaced with a normalized deep copy,
+// which ensures that every string in untyped payloads such as map[string]any is reached.
 //
 // Example:
 //
@@ -30,74 +31,13 @@ func Apply(v any, normalizers ...Normalizer) {
 		return
 	}
 	rv := reflect.ValueOf(v)
-	applyValue(rv, normalizers)
+	planFor(rv.Type()).run(rv, normalizers)
 }
 
-func applyValue(rv reflect.Value, normalizers []Normalizer) {
-	if !rv.IsValid() {
-		return
-	}
-
-	switch rv.Kind() {
-	case reflect.Ptr:
-		if !rv.IsNil() {
-			applyValue(rv.Elem(), normalizers)
-		}
-	case reflect.Interface:
-		if !rv.IsNil() {
-			elem := rv.Elem()
-			if elem.Kind() == reflect.String {
-				str := elem.String()
-				for _, n := range normalizers {
-					str = n(str)
-				}
-				rv.Set(reflect.ValueOf(str))
-			} else {
-				applyValue(elem, normalizers)
-			}
-		}
-	case reflect.Struct:
-		for i := 0; i < rv.NumField(); i++ {
-			field := rv.Field(i)
-			if field.CanSet() {
-				applyValue(field, normalizers)
-			} else if field.Kind() == reflect.Struct || field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
-				applyValue(field, normalizers)
-			}
-		}
-	case reflect.Slice, reflect.Array:
-		for i := 0; i < rv.Len(); i++ {
-			applyValue(rv.Index(i), normalizers)
-		}
-	case reflect.Map:
-		for _, key := range rv.MapKeys() {
-			val := rv.MapIndex(key)
-			if val.Kind() == reflect.String {
-				str := val.String()
-				for _, n := range normalizers {
-					str = n(str)
-				}
-				rv.SetMapIndex(key, reflect.ValueOf(str))
-			} else if val.Kind() == reflect.Interface {
-				// Handle interface{} values that might contain strings
-				if val.Elem().Kind() == reflect.String {
-					str := val.Elem().String()
-					for _, n := range normalizers {
-						str = n(str)
-					}
-					rv.SetMapIndex(key, reflect.ValueOf(str))
-				} else {
-					applyValue(val, normalizers)
-				}
-			} else {
-				applyValue(val, normalizers)
-			}
-		}
-	case reflect.String:
-		str := rv.String()
-		for _, n := range normalizers {
-			str = n(str)
-		}
-		rv.SetString(str)
+// normalize runs s through each of the normalizers in turn.
+func normalize(s string, normalizers []Normalizer) string {
+	for _, n := range normalizers {
+		s = n(s)
 	}
+	return s
 }
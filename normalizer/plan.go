@@ -0,0 +1,287 @@
+package normalizer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// A plan applies normalizers to every string reachable from a value of a
+// single type. Plans are compiled once per type and cached, so that the cost
+// of inspecting a type with reflection is paid on the first call to Apply
+// rather than on every value.
+type plan struct {
+	// apply is nil for types that cannot hold any strings, such as numbers,
+	// which lets their parents skip them entirely.
+	apply func(rv reflect.Value, normalizers []Normalizer)
+
+	// done is false while the plan is being compiled. A type that refers to
+	// itself sees its own unfinished plan, which must not be taken for one
+	// that does nothing.
+	done bool
+}
+
+func (p *plan) run(rv reflect.Value, normalizers []Normalizer) {
+	if p.apply != nil {
+		p.apply(rv, normalizers)
+	}
+}
+
+// noop reports whether the plan is known to do nothing.
+func (p *plan) noop() bool {
+	return p.done && p.apply == nil
+}
+
+var (
+	// plans maps a reflect.Type to its *plan.
+	plans   sync.Map
+	compile sync.Mutex
+)
+
+// planFor returns the plan for t, compiling it if needed.
+func planFor(t reflect.Type) *plan {
+	if p, ok := plans.Load(t); ok {
+		return p.(*plan)
+	}
+
+	compile.Lock()
+	defer compile.Unlock()
+	if p, ok := plans.Load(t); ok {
+		return p.(*plan)
+	}
+
+	// Plans for self-referencing types point at each other, so none of them
+	// are published until all of them are finished.
+	compiling := make(map[reflect.Type]*plan)
+	p := compilePlan(t, compiling)
+	for t, p := range compiling {
+		plans.Store(t, p)
+	}
+	return p
+}
+
+func compilePlan(t reflect.Type, compiling map[reflect.Type]*plan) *plan {
+	if p, ok := plans.Load(t); ok {
+		return p.(*plan)
+	}
+	if p, ok := compiling[t]; ok {
+		return p
+	}
+
+	p := &plan{}
+	compiling[t] = p
+	defer func() { p.done = true }()
+
+	switch t.Kind() {
+	case reflect.String:
+		p.apply = applyString
+	case reflect.Ptr:
+		p.apply = compilePtr(t, compiling)
+	case reflect.Interface:
+		p.apply = applyInterface
+	case reflect.Struct:
+		p.apply = compileStruct(t, compiling)
+	case reflect.Slice, reflect.Array:
+		p.apply = compileList(t, compiling)
+	case reflect.Map:
+		p.apply = compileMap(t, compiling)
+	}
+	return p
+}
+
+func applyString(rv reflect.Value, normalizers []Normalizer) {
+	if rv.CanSet() {
+		rv.SetString(normalize(rv.String(), normalizers))
+	}
+}
+
+// applyInterface normalizes the value held by an interface. The value held
+// by an interface cannot be modified in place unless it is a pointer or refers
+// to other values, as slices and maps do, so strings, structs and arrays are
+// replaced with a normalized copy when the interface can be set.
+func applyInterface(rv reflect.Value, normalizers []Normalizer) {
+	if rv.IsNil() {
+		return
+	}
+	elem := rv.Elem()
+	switch elem.Kind() {
+	case reflect.String:
+		if rv.CanSet() {
+			rv.Set(reflect.ValueOf(normalize(elem.String(), normalizers)))
+		}
+		return
+	case reflect.Struct, reflect.Array:
+		if rv.CanSet() {
+			rv.Set(normalizedCopy(elem, normalizers))
+			return
+		}
+	}
+	planFor(elem.Type()).run(elem, normalizers)
+}
+
+func compilePtr(t reflect.Type, compiling map[reflect.Type]*plan) func(reflect.Value, []Normalizer) {
+	elem := compilePlan(t.Elem(), compiling)
+	if elem.noop() {
+		return nil
+	}
+	return func(rv reflect.Value, normalizers []Normalizer) {
+		if !rv.IsNil() {
+			elem.run(rv.Elem(), normalizers)
+		}
+	}
+}
+
+// fieldPlan is the plan for a single field of a struct.
+type fieldPlan struct {
+	index int
+	plan  *plan
+
+	// own is set for fields whose tag names their normalizers.
+	own         bool
+	normalizers []Normalizer
+
+	// Fields that cannot be set, such as unexported ones, may still lead to
+	// values that can, so they are visited if they are a struct, pointer or
+	// interface.
+	visitReadOnly bool
+}
+
+func compileStruct(t reflect.Type, compiling map[reflect.Type]*plan) func(reflect.Value, []Normalizer) {
+	var fields []fieldPlan
+	for i := range t.NumField() {
+		field := t.Field(i)
+		pipeline, own, skip := fieldPipeline(field)
+		if skip {
+			continue
+		}
+		p := compilePlan(field.Type, compiling)
+		if p.noop() {
+			continue
+		}
+		kind := field.Type.Kind()
+		fields = append(fields, fieldPlan{
+			index:         i,
+			plan:          p,
+			own:           own,
+			normalizers:   pipeline,
+			visitReadOnly: kind == reflect.Struct || kind == reflect.Ptr || kind == reflect.Interface,
+		})
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return func(rv reflect.Value, normalizers []Normalizer) {
+		for _, f := range fields {
+			field := rv.Field(f.index)
+			if !f.visitReadOnly && !field.CanSet() {
+				continue
+			}
+			if f.own {
+				f.plan.run(field, f.normalizers)
+			} else {
+				f.plan.run(field, normalizers)
+			}
+		}
+	}
+}
+
+func compileList(t reflect.Type, compiling map[reflect.Type]*plan) func(reflect.Value, []Normalizer) {
+	elem := compilePlan(t.Elem(), compiling)
+	if elem.noop() {
+		return nil
+	}
+	return func(rv reflect.Value, normalizers []Normalizer) {
+		for i := range rv.Len() {
+			elem.run(rv.Index(i), normalizers)
+		}
+	}
+}
+
+// compileMap compiles the plan for a map. Map values cannot be modified in
+// place, so each one is replaced with a normalized copy stored back under its
+// key.
+func compileMap(t reflect.Type, compiling map[reflect.Type]*plan) func(reflect.Value, []Normalizer) {
+	kind := t.Elem().Kind()
+	if kind != reflect.Interface && compilePlan(t.Elem(), compiling).noop() {
+		return nil
+	}
+	return func(rv reflect.Value, normalizers []Normalizer) {
+		// Maps reached through unexported fields cannot be modified.
+		if !rv.CanInterface() {
+			return
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			val := iter.Value()
+			if kind == reflect.Interface && !val.IsNil() && val.Elem().Kind() == reflect.String {
+				rv.SetMapIndex(iter.Key(), reflect.ValueOf(normalize(val.Elem().String(), normalizers)))
+				continue
+			}
+			rv.SetMapIndex(iter.Key(), normalizedCopy(val, normalizers))
+		}
+	}
+}
+
+// normalizedCopy returns a normalized copy of v, which need not be
+// addressable. Slices, arrays and maps are copied all the way down so that
+// every string they hold can be normalized, leaving v itself untouched.
+// Values behind pointers are normalized in place, as they would be anywhere
+// else.
+func normalizedCopy(v reflect.Value, normalizers []Normalizer) reflect.Value {
+	t := v.Type()
+	if planFor(t).noop() {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(normalize(v.String(), normalizers)).Convert(t)
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(t).Elem()
+		if elem := v.Elem(); elem.Kind() == reflect.String {
+			out.Set(reflect.ValueOf(normalize(elem.String(), normalizers)))
+		} else {
+			out.Set(normalizedCopy(elem, normalizers))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := range v.Len() {
+			out.Index(i).Set(normalizedCopy(v.Index(i), normalizers))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(t).Elem()
+		for i := range v.Len() {
+			out.Index(i).Set(normalizedCopy(v.Index(i), normalizers))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), iter.Value())
+		}
+		planFor(t).run(out, normalizers)
+		return out
+	case reflect.Struct:
+		// Copying the struct makes its fields settable, and its plan takes
+		// care of any tags.
+		out := reflect.New(t).Elem()
+		out.Set(v)
+		planFor(t).run(out, normalizers)
+		return out
+	}
+
+	planFor(t).run(v, normalizers)
+	return v
+}
@@ -0,0 +1,207 @@
+package normalizer
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// legacyApplyValue is the reflection walk Apply used before plans were
+// introduced. Plans must behave exactly like it.
+func legacyApplyValue(rv reflect.Value, normalizers []Normalizer) {
+	if !rv.IsValid() {
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			legacyApplyValue(rv.Elem(), normalizers)
+		}
+	case reflect.Interface:
+		if !rv.IsNil() {
+			elem := rv.Elem()
+			if elem.Kind() == reflect.String {
+				str := elem.String()
+				for _, n := range normalizers {
+					str = n(str)
+				}
+				rv.Set(reflect.ValueOf(str))
+			} else {
+				legacyApplyValue(elem, normalizers)
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Field(i)
+			normalizers, ok := legacyFieldNormalizers(rv.Type().Field(i), normalizers)
+			if !ok {
+				continue
+			}
+			if field.CanSet() {
+				legacyApplyValue(field, normalizers)
+			} else if field.Kind() == reflect.Struct || field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+				legacyApplyValue(field, normalizers)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			legacyApplyValue(rv.Index(i), normalizers)
+		}
+	case reflect.Map:
+		for _, key := range rv.MapKeys() {
+			val := rv.MapIndex(key)
+			if val.Kind() == reflect.String {
+				str := val.String()
+				for _, n := range normalizers {
+					str = n(str)
+				}
+				rv.SetMapIndex(key, reflect.ValueOf(str))
+			} else if val.Kind() == reflect.Interface {
+				// Handle interface{} values that might contain strings
+				if val.Elem().Kind() == reflect.String {
+					str := val.Elem().String()
+					for _, n := range normalizers {
+						str = n(str)
+					}
+					rv.SetMapIndex(key, reflect.ValueOf(str))
+				} else {
+					legacyApplyValue(val, normalizers)
+				}
+			} else {
+				legacyApplyValue(val, normalizers)
+			}
+		}
+	case reflect.String:
+		str := rv.String()
+		for _, n := range normalizers {
+			str = n(str)
+		}
+		rv.SetString(str)
+	}
+}
+
+func legacyFieldNormalizers(field reflect.StructField, normalizers []Normalizer) ([]Normalizer, bool) {
+	pipeline, own, skip := fieldPipeline(field)
+	if skip {
+		return nil, false
+	}
+	if own {
+		return pipeline, true
+	}
+	return normalizers, true
+}
+
+type planNode struct {
+	Name     string
+	Count    int
+	Children []*planNode
+	Parent   *planNode `normalize:"-"`
+}
+
+type planRecord struct {
+	Title    string
+	Ref      string `normalize:"-"`
+	Note     string `normalize:"html"`
+	Numbers  []int
+	Tags     []string
+	Array    [2]string
+	Meta     map[string]string
+	Extra    map[string]any
+	Any      any
+	Pointer  *string
+	Nested   struct{ Text []string }
+	Tree     *planNode
+	hidden   string
+	internal *planNode
+}
+
+func newPlanRecord() *planRecord {
+	s := "pointer &amp; \u201cquoted\u201d"
+	root := &planNode{Name: "root &amp; \u2026", Count: 1}
+	root.Children = []*planNode{{Name: "child \u2014 one", Parent: root}}
+	return &planRecord{
+		Title:   "Title &amp; \u201cquotes\u201d\u2026",
+		Ref:     "Beha\u2019alotcha 1:1 &amp;",
+		Note:    "note &amp; \u2019",
+		Numbers: []int{1, 2, 3},
+		Tags:    []string{"a &amp; b", "c \u2014 d"},
+		Array:   [2]string{"x &lt; y", "\u2026"},
+		Meta:    map[string]string{"k": "v &amp; w"},
+		Extra: map[string]any{
+			"str":   "e &amp; f",
+			"list":  []any{"g &amp; h", 1.0, map[string]any{"deep": "i &amp; j"}},
+			"map":   map[string]any{"k": "l &amp; m"},
+			"nil":   nil,
+			"num":   2.0,
+			"slice": []string{"n &amp; o"},
+		},
+		Any:      []string{"p &amp; q"},
+		Pointer:  &s,
+		Nested:   struct{ Text []string }{Text: []string{"r &amp; s"}},
+		Tree:     root,
+		hidden:   "hidden &amp;",
+		internal: &planNode{Name: "internal &amp;"},
+	}
+}
+
+func TestApply_MatchesLegacy(t *testing.T) {
+	normalizers := []Normalizer{HTMLUnescape, UnicodeNFC, Punctuation}
+
+	got := newPlanRecord()
+	Apply(got, normalizers...)
+
+	want := newPlanRecord()
+	legacyApplyValue(reflect.ValueOf(want), normalizers)
+
+	// The tree refers back to its root, which DeepEqual handles, but the
+	// failure message would not, so compare it separately.
+	if !reflect.DeepEqual(got.Tree.Children[0].Name, want.Tree.Children[0].Name) {
+		t.Errorf("Tree = %q, want %q", got.Tree.Children[0].Name, want.Tree.Children[0].Name)
+	}
+	got.Tree, want.Tree = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+}
+
+func TestApply_NonStringTypes(t *testing.T) {
+	ints := []int{1, 2, 3}
+	Apply(&ints, strings.ToUpper)
+	if !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Errorf("Apply() = %v", ints)
+	}
+
+	counts := map[string]int{"a": 1}
+	Apply(&counts, strings.ToUpper)
+	if !reflect.DeepEqual(counts, map[string]int{"a": 1}) {
+		t.Errorf("Apply() = %v", counts)
+	}
+}
+
+func TestApply_NamedStringMap(t *testing.T) {
+	type label string
+	labels := map[string]label{"a": "x &amp; y"}
+	Apply(&labels, HTMLUnescape)
+
+	if labels["a"] != "x & y" {
+		t.Errorf("Apply() = %q, want %q", labels["a"], "x & y")
+	}
+}
+
+func TestApply_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := newPlanRecord()
+			Apply(r, HTMLUnescape)
+			if r.Title != "Title & \u201cquotes\u201d\u2026" {
+				t.Errorf("Title = %q", r.Title)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -2,6 +2,14 @@ package normalizer
 
 import "strings"
 
+var punctuation = strings.NewReplacer(
+	"“", `"`, "”", `"`,
+	"‘", `'`, "’", `'`,
+	"—", "-", "–", "-",
+	"…", "...",
+	"\u00A0", " ",
+)
+
 // Punctuation replaces fancy/smart punctuation characters with standard ASCII equivalents.
 // This normalizer converts various Unicode punctuation marks to their basic ASCII counterparts:
 //
@@ -18,12 +26,5 @@ import "strings"
 //
 //	Punctuation(""Hello" — he said…") // Returns ""Hello" - he said..."
 func Punctuation(s string) string {
-	replacer := strings.NewReplacer(
-		"“", `"`, "”", `"`,
-		"‘", `'`, "’", `'`,
-		"—", "-", "–", "-",
-		"…", "...",
-		"\u00A0", " ",
-	)
-	return replacer.Replace(s)
+	return punctuation.Replace(s)
 }
@@ -0,0 +1,83 @@
+package normalizer
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TagName is the struct tag that controls how a field is normalized.
+//
+// A field tagged `normalize:"-"` is left untouched, along with everything it
+// contains. This is meant for values such as refs, URLs and slugs, where
+// changing a single character changes their meaning.
+//
+// A field tagged with a comma separated list of names, such as
+// `normalize:"html,nfc"`, is normalized with only the named normalizers, in
+// the order given, instead of those passed to Apply. Names that have not been
+// registered are ignored.
+const TagName = "normalize"
+
+var (
+	namedMu sync.RWMutex
+	named   = map[string]Normalizer{
+		"html":               HTMLUnescape,
+		"nfc":                UnicodeNFC,
+		"punctuation":        Punctuation,
+		"niqqud":             StripNiqqud,
+		"cantillation":       StripCantillation,
+		"final-letters":      FoldFinalLetters,
+		"hebrew-punctuation": HebrewPunctuation,
+		"parasha-markers":    StripParashaMarkers,
+		"ktiv-qere":          CollapseKtivQere,
+		"strip-tags":         StripTags,
+		"sanitize":           SanitizeHTML(DefaultAllowedTags...),
+	}
+)
+
+// Register makes a normalizer available to struct tags under the given name,
+// replacing any normalizer already registered under it.
+//
+// Example:
+//
+//	normalizer.Register("trim", strings.TrimSpace)
+//
+//	type Entry struct {
+//		Headword string `normalize:"trim,nfc"`
+//	}
+func Register(name string, n Normalizer) {
+	namedMu.Lock()
+	named[name] = n
+	namedMu.Unlock()
+
+	// Plans resolve the names in tags when they are compiled.
+	plans.Clear()
+}
+
+// Lookup returns the normalizer registered under name.
+func Lookup(name string) (Normalizer, bool) {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	n, ok := named[name]
+	return n, ok
+}
+
+// fieldPipeline reads the normalize tag of a struct field. It reports whether
+// the field is skipped and whether it names its own normalizers rather than
+// using those of the struct that holds it.
+func fieldPipeline(field reflect.StructField) (pipeline []Normalizer, own, skip bool) {
+	tag, ok := field.Tag.Lookup(TagName)
+	if !ok || tag == "" {
+		return nil, false, false
+	}
+	if tag == "-" {
+		return nil, false, true
+	}
+
+	for name := range strings.SplitSeq(tag, ",") {
+		if n, ok := Lookup(strings.TrimSpace(name)); ok {
+			pipeline = append(pipeline, n)
+		}
+	}
+	return pipeline, true, false
+}
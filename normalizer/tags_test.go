@@ -0,0 +1,100 @@
+package normalizer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApply_Tags(t *testing.T) {
+	type Link struct {
+		URL   string
+		Title string
+	}
+
+	type Record struct {
+		Title   string
+		Ref     string            `normalize:"-"`
+		Source  string            `normalize:"html,nfc"`
+		Names   []string          `normalize:"punctuation"`
+		Links   []Link            `normalize:"-"`
+		Nested  Link              `normalize:"html"`
+		Meta    map[string]string `normalize:""`
+		Unknown string            `normalize:"nonexistent"`
+	}
+
+	input := &Record{
+		Title:   "Beha" + string(rune(0x2019)) + "alotcha &amp; more",
+		Ref:     "Numbers 8:1 &amp; Beha" + string(rune(0x2019)) + "alotcha",
+		Source:  "https://example.com/?a=1&amp;b=2 " + string(rune(0x2014)) + " cafe" + string(rune(0x0301)),
+		Names:   []string{"Ha" + string(rune(0x2019)) + "azinu &amp;"},
+		Links:   []Link{{URL: "a&amp;b", Title: "x &amp; y"}},
+		Nested:  Link{URL: "a&amp;b", Title: "Sh" + string(rune(0x2019)) + "lach"},
+		Meta:    map[string]string{"k": "v &amp; " + string(rune(0x2026))},
+		Unknown: "left &amp; alone",
+	}
+
+	Apply(input, HTMLUnescape, Punctuation)
+
+	expected := &Record{
+		Title:   "Beha'alotcha & more",
+		Ref:     "Numbers 8:1 &amp; Beha" + string(rune(0x2019)) + "alotcha",
+		Source:  "https://example.com/?a=1&b=2 " + string(rune(0x2014)) + " café",
+		Names:   []string{"Ha'azinu &amp;"},
+		Links:   []Link{{URL: "a&amp;b", Title: "x &amp; y"}},
+		Nested:  Link{URL: "a&b", Title: "Sh" + string(rune(0x2019)) + "lach"},
+		Meta:    map[string]string{"k": "v & ..."},
+		Unknown: "left &amp; alone",
+	}
+
+	if !reflect.DeepEqual(input, expected) {
+		t.Errorf("Apply() = %+v, want %+v", input, expected)
+	}
+}
+
+func TestApply_TagsOnPointerField(t *testing.T) {
+	type Inner struct {
+		Text string
+		Ref  string `normalize:"-"`
+	}
+	type Outer struct {
+		Inner *Inner
+		Skip  *Inner `normalize:"-"`
+	}
+
+	input := &Outer{
+		Inner: &Inner{Text: "a &amp; b", Ref: "a &amp; b"},
+		Skip:  &Inner{Text: "a &amp; b", Ref: "a &amp; b"},
+	}
+	Apply(input, HTMLUnescape)
+
+	if input.Inner.Text != "a & b" || input.Inner.Ref != "a &amp; b" {
+		t.Errorf("Inner = %+v", input.Inner)
+	}
+	if input.Skip.Text != "a &amp; b" {
+		t.Errorf("Skip = %+v", input.Skip)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-upper", strings.ToUpper)
+	defer func() {
+		namedMu.Lock()
+		delete(named, "test-upper")
+		namedMu.Unlock()
+	}()
+
+	if _, ok := Lookup("test-upper"); !ok {
+		t.Fatal("Lookup() did not find registered normalizer")
+	}
+
+	type Entry struct {
+		Word string `normalize:"html, test-upper"`
+	}
+	e := &Entry{Word: "a &amp; b"}
+	Apply(e)
+
+	if e.Word != "A & B" {
+		t.Errorf("Apply() = %q, want %q", e.Word, "A & B")
+	}
+}
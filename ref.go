@@ -0,0 +1,126 @@
+package sefaria
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Ref is a parsed citation of a text. A Ref consists of the title of a book
+// followed optionally by the sections that narrow it down, such as the
+// chapter and verse, and for ranges the sections it extends to.
+//
+// Sections are kept as strings since not every address is numeric. Talmud
+// folios for example are addressed as "2a" or "2b".
+type Ref struct {
+	Book       string   `json:"book"`
+	Sections   []string `json:"sections,omitempty"`
+	ToSections []string `json:"toSections,omitempty"`
+}
+
+var ErrEmptyRef = errors.New("ref cannot be empty")
+
+// ParseRef parses a canonical ref such as "Genesis 1:1", "Berakhot 2a:5" or
+// "Genesis 1:1-2:3". Anything that precedes the address is taken to be the
+// book, which allows for titles containing spaces, commas and numbers.
+func ParseRef(s string) (Ref, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Ref{}, ErrEmptyRef
+	}
+
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		return Ref{Book: s}, nil
+	}
+
+	sections, toSections, ok := parseAddress(s[i+1:])
+	if !ok {
+		return Ref{Book: s}, nil
+	}
+
+	return Ref{
+		Book:       strings.TrimRight(s[:i], " ,"),
+		Sections:   sections,
+		ToSections: toSections,
+	}, nil
+}
+
+// parseAddress parses the address portion of a ref, e.g. "1:1-2:3".
+func parseAddress(s string) (sections, toSections []string, ok bool) {
+	from, to, isRange := strings.Cut(s, "-")
+	sections = strings.Split(from, ":")
+	for _, section := range sections {
+		if !isSection(section) {
+			return nil, nil, false
+		}
+	}
+	if !isRange {
+		return sections, nil, true
+	}
+
+	toSections = strings.Split(to, ":")
+	if len(toSections) > len(sections) {
+		return nil, nil, false
+	}
+	for _, section := range toSections {
+		if !isSection(section) {
+			return nil, nil, false
+		}
+	}
+
+	// A range only spells out the sections that change, so "1:1-3" ends at
+	// "1:3". Fill in the rest from the start of the range.
+	full := make([]string, len(sections))
+	copy(full, sections[:len(sections)-len(toSections)])
+	copy(full[len(sections)-len(toSections):], toSections)
+	return sections, full, true
+}
+
+// isSection reports whether s is a numeric section, optionally followed by
+// the side of a folio.
+func isSection(s string) bool {
+	if strings.HasSuffix(s, "a") || strings.HasSuffix(s, "b") {
+		s = s[:len(s)-1]
+	}
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the ref in Sefaria's canonical form. Ranges only repeat the
+// sections that differ, so a ref from 1:1 to 1:3 is written "1:1-3".
+func (r Ref) String() string {
+	var b strings.Builder
+	b.WriteString(r.Book)
+	if len(r.Sections) == 0 {
+		return b.String()
+	}
+	b.WriteByte(' ')
+	b.WriteString(strings.Join(r.Sections, ":"))
+
+	if len(r.ToSections) == 0 || len(r.ToSections) != len(r.Sections) {
+		return b.String()
+	}
+	same := 0
+	for same < len(r.Sections)-1 && r.Sections[same] == r.ToSections[same] {
+		same++
+	}
+	if same == len(r.Sections)-1 && r.Sections[same] == r.ToSections[same] {
+		return b.String()
+	}
+	b.WriteByte('-')
+	b.WriteString(strings.Join(r.ToSections[same:], ":"))
+	return b.String()
+}
+
+// IsRange reports whether the ref spans more than one section or segment.
+func (r Ref) IsRange() bool {
+	return len(r.ToSections) > 0 && !slices.Equal(r.Sections, r.ToSections)
+}
@@ -0,0 +1,88 @@
+package sefaria
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSection(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"1", true},
+		{"12", true},
+		{"2a", true},
+		{"2b", true},
+		{"176b", true},
+		{"", false},
+		{"a", false},
+		{"b", false},
+		{"ab", false},
+		{"2ba", false},
+		{"2ab", false},
+		{"2aa", false},
+		{"2bb", false},
+		{"2c", false},
+		{"a2", false},
+		{"2a5", false},
+		{"-1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isSection(tt.in), "isSection(%q)", tt.in)
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Ref
+	}{
+		{"Genesis", Ref{Book: "Genesis"}},
+		{"Genesis 1", Ref{Book: "Genesis", Sections: []string{"1"}}},
+		{"Genesis 1:1", Ref{Book: "Genesis", Sections: []string{"1", "1"}}},
+		{"Berakhot 2a:5", Ref{Book: "Berakhot", Sections: []string{"2a", "5"}}},
+		{"Genesis 1:1-3", Ref{Book: "Genesis", Sections: []string{"1", "1"}, ToSections: []string{"1", "3"}}},
+		{"Genesis 1:1-2:3", Ref{Book: "Genesis", Sections: []string{"1", "1"}, ToSections: []string{"2", "3"}}},
+		{"Berakhot 2a-3b", Ref{Book: "Berakhot", Sections: []string{"2a"}, ToSections: []string{"3b"}}},
+		{"Rashi on Genesis 1:1:1", Ref{Book: "Rashi on Genesis", Sections: []string{"1", "1", "1"}}},
+		{"Mishneh Torah, Repentance 1:1", Ref{Book: "Mishneh Torah, Repentance", Sections: []string{"1", "1"}}},
+		{"  Genesis 1:1  ", Ref{Book: "Genesis", Sections: []string{"1", "1"}}},
+
+		// Malformed addresses are taken to be part of the title.
+		{"Berakhot 2ba", Ref{Book: "Berakhot 2ba"}},
+		{"Berakhot 2ab:5", Ref{Book: "Berakhot 2ab:5"}},
+		{"Berakhot 2a-3ba", Ref{Book: "Berakhot 2a-3ba"}},
+		{"Genesis 1:1-1:2:3", Ref{Book: "Genesis 1:1-1:2:3"}},
+		{"Genesis 1::1", Ref{Book: "Genesis 1::1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRef(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRef_Empty(t *testing.T) {
+	_, err := ParseRef("   ")
+	assert.ErrorIs(t, err, ErrEmptyRef)
+}
+
+func TestRef_String(t *testing.T) {
+	for _, s := range []string{
+		"Genesis",
+		"Genesis 1:1",
+		"Genesis 1:1-3",
+		"Genesis 1:1-2:3",
+		"Berakhot 2a:5",
+		"Berakhot 2a-3b",
+	} {
+		ref, err := ParseRef(s)
+		require.NoError(t, err)
+		assert.Equal(t, s, ref.String())
+	}
+}
@@ -24,8 +24,11 @@ func (s *RelatedService) Get(ctx context.Context, tref string) (*RelatedContent,
 }
 
 type RelatedLinksOptions struct {
-	WithText       bool `url:"with_text,omitempty"`
-	WithSheetLinks bool `url:"with_sheet_links,omitempty"`
+	// Include the text of each linked ref.
+	WithText bool `url:"with_text,omitempty" desc:"include the text of each link"`
+
+	// Include links from source sheets.
+	WithSheetLinks bool `url:"with_sheet_links,omitempty" desc:"include links from source sheets"`
 }
 
 func (s *RelatedService) Links(ctx context.Context, tref string, opts *RelatedLinksOptions) (*RelatedContent, error) {
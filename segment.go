@@ -0,0 +1,268 @@
+package sefaria
+
+import (
+	"iter"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Segment is a single segment of a text, such as a verse, reduced to plain
+// text with its footnotes set apart.
+type Segment struct {
+	// The text without any markup or footnotes.
+	Text string `json:"text"`
+
+	Footnotes []Footnote `json:"footnotes,omitempty"`
+
+	// The refs and topics linked from the text, as found in segments
+	// fetched with FormatWrapAllEntities.
+	Entities []Entity `json:"entities,omitempty"`
+}
+
+// Footnote is a note attached to a segment.
+type Footnote struct {
+	// The marker shown in the text, such as "1" or "*". Empty for notes that
+	// have no marker.
+	Marker string `json:"marker,omitempty"`
+
+	// The text of the note without any markup.
+	Text string `json:"text"`
+
+	// The byte offset within the segment's text where the marker appeared.
+	Offset int `json:"offset"`
+}
+
+// EntityKind is the kind of thing an entity links to.
+type EntityKind string
+
+const (
+	EntityRef         EntityKind = "ref"
+	EntityTopic       EntityKind = "topic"
+	EntityNamedEntity EntityKind = "named-entity"
+)
+
+// Entity is a link from a span of a segment's text to a ref or topic.
+type Entity struct {
+	Kind EntityKind `json:"kind"`
+
+	// Start and End are the byte offsets of the linked span within the
+	// segment's text, so that Text == seg.Text[Start:End].
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+
+	// The ref linked to by refs, and the slug of the topic linked to by
+	// topics and named entities.
+	Ref  string `json:"ref,omitempty" normalize:"-"`
+	Slug string `json:"slug,omitempty" normalize:"-"`
+}
+
+// ParseSegment parses the HTML of a segment as returned by TextService.Get.
+// Sefaria marks up a footnote as a <sup class="footnote-marker"> holding its
+// marker followed by an <i class="footnote"> holding its text. Both are taken
+// out of the segment's text and returned as footnotes.
+//
+// Links to refs and topics, which are added by FormatWrapAllEntities, are
+// returned as entities. All other tags are stripped, and line breaks become
+// newlines.
+func ParseSegment(s string) Segment {
+	var (
+		seg  Segment
+		text strings.Builder
+
+		// The marker or note being read, if any, and how many of its
+		// elements are open.
+		marker, note strings.Builder
+		inMarker     int
+		inNote       int
+
+		// Whether the last footnote is still waiting for its text.
+		pending bool
+
+		// The entity whose link is open, if any.
+		entity *Entity
+	)
+
+	write := func(s string) {
+		switch {
+		case inMarker > 0:
+			marker.WriteString(s)
+		case inNote > 0:
+			note.WriteString(s)
+		default:
+			text.WriteString(s)
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tok := z.Token()
+
+		switch tt {
+		case html.TextToken:
+			write(tok.Data)
+		case html.SelfClosingTagToken:
+			if tok.Data == "br" {
+				write("\n")
+			}
+		case html.StartTagToken:
+			switch {
+			case tok.Data == "br":
+				write("\n")
+			case tok.Data == "script" || tok.Data == "style":
+				skipElement(z, tok.Data)
+			case inMarker > 0 && tok.Data == "sup":
+				inMarker++
+			case inNote > 0 && tok.Data == "i":
+				inNote++
+			case inMarker == 0 && inNote == 0 && entity == nil && tok.Data == "a":
+				if e, ok := linkedEntity(tok); ok {
+					e.Start = text.Len()
+					entity = &e
+				}
+			case inMarker == 0 && inNote == 0 && tok.Data == "sup" && hasClass(tok, "footnote-marker"):
+				inMarker = 1
+				marker.Reset()
+			case inMarker == 0 && inNote == 0 && tok.Data == "i" && hasClass(tok, "footnote"):
+				inNote = 1
+				note.Reset()
+				if !pending {
+					seg.Footnotes = append(seg.Footnotes, Footnote{Offset: text.Len()})
+				}
+			}
+		case html.EndTagToken:
+			switch {
+			case entity != nil && inMarker == 0 && inNote == 0 && tok.Data == "a":
+				entity.End = text.Len()
+				entity.Text = text.String()[entity.Start:entity.End]
+				seg.Entities = append(seg.Entities, *entity)
+				entity = nil
+			case inMarker > 0 && tok.Data == "sup":
+				if inMarker--; inMarker == 0 {
+					seg.Footnotes = append(seg.Footnotes, Footnote{
+						Marker: strings.TrimSpace(marker.String()),
+						Offset: text.Len(),
+					})
+					pending = true
+				}
+			case inNote > 0 && tok.Data == "i":
+				if inNote--; inNote == 0 {
+					seg.Footnotes[len(seg.Footnotes)-1].Text = strings.TrimSpace(note.String())
+					pending = false
+				}
+			}
+		}
+	}
+
+	seg.Text = text.String()
+	return seg
+}
+
+// linkedEntity reads the entity an <a> tag links to. Refs carry their ref in
+// data-ref, and named entities carry the slug of their topic in data-slug.
+// Otherwise the entity is read from the link itself.
+func linkedEntity(tok html.Token) (Entity, bool) {
+	var ref, slug, href string
+	for _, a := range tok.Attr {
+		switch a.Key {
+		case "data-ref":
+			ref = a.Val
+		case "data-slug":
+			slug = a.Val
+		case "href":
+			href = a.Val
+		}
+	}
+
+	var page *Page
+	if href != "" {
+		page, _ = ParseURL(href)
+	}
+
+	switch {
+	case ref != "" || hasClass(tok, "refLink"):
+		if ref == "" && page != nil && page.Kind == PageReader {
+			ref = page.Ref
+		}
+		return Entity{Kind: EntityRef, Ref: ref}, ref != ""
+	case hasClass(tok, "namedEntityLink"):
+		if slug == "" && page != nil && page.Kind == PageTopic {
+			slug = page.Topic
+		}
+		return Entity{Kind: EntityNamedEntity, Slug: slug}, slug != ""
+	case page != nil && page.Kind == PageTopic:
+		return Entity{Kind: EntityTopic, Slug: page.Topic}, true
+	}
+	return Entity{}, false
+}
+
+// skipElement advances z past the end of the element it just entered.
+func skipElement(z *html.Tokenizer, name string) {
+	for depth := 1; depth > 0; {
+		switch z.Next() {
+		case html.ErrorToken:
+			return
+		case html.StartTagToken:
+			if n, _ := z.TagName(); string(n) == name {
+				depth++
+			}
+		case html.EndTagToken:
+			if n, _ := z.TagName(); string(n) == name {
+				depth--
+			}
+		}
+	}
+}
+
+func hasClass(tok html.Token, class string) bool {
+	for _, a := range tok.Attr {
+		if a.Key == "class" && slices.Contains(strings.Fields(a.Val), class) {
+			return true
+		}
+	}
+	return false
+}
+
+// Segments parses each segment of the English text. See ParseSegment.
+func (t *Text) Segments() []Segment {
+	return parseSegments(t.Text)
+}
+
+// HeSegments parses each segment of the Hebrew text. See ParseSegment.
+func (t *Text) HeSegments() []Segment {
+	return parseSegments(t.He)
+}
+
+// SegmentPairs iterates over the English and Hebrew segments together,
+// aligned by their position in the text. When one language has fewer
+// segments than the other, its side of the remaining pairs is empty.
+func (t *Text) SegmentPairs() iter.Seq2[Segment, Segment] {
+	return func(yield func(Segment, Segment) bool) {
+		for i := range max(len(t.Text), len(t.He)) {
+			var en, he Segment
+			if i < len(t.Text) {
+				en = ParseSegment(t.Text[i])
+			}
+			if i < len(t.He) {
+				he = ParseSegment(t.He[i])
+			}
+			if !yield(en, he) {
+				return
+			}
+		}
+	}
+}
+
+func parseSegments(texts []string) []Segment {
+	segments := make([]Segment, len(texts))
+	for i, s := range texts {
+		segments[i] = ParseSegment(s)
+	}
+	return segments
+}
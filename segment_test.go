@@ -0,0 +1,211 @@
+package sefaria
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSegment_Footnotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		text      string
+		footnotes []Footnote
+	}{
+		{
+			name: "marker and note",
+			html: `When God began to create<sup class="footnote-marker">a</sup><i class="footnote">Others “In the beginning God created.”</i> heaven and earth—`,
+			text: "When God began to create heaven and earth—",
+			footnotes: []Footnote{
+				{Marker: "a", Text: "Others “In the beginning God created.”", Offset: 24},
+			},
+		},
+		{
+			name: "markup within the note",
+			html: `and a wind<sup class="footnote-marker">*</sup><i class="footnote"><b>a wind</b> Or “a <i>divine</i> wind.”</i> from God sweeping over the water—`,
+			text: "and a wind from God sweeping over the water—",
+			footnotes: []Footnote{
+				{Marker: "*", Text: "a wind Or “a divine wind.”", Offset: 10},
+			},
+		},
+		{
+			name: "several notes",
+			html: `God called the light Day<sup class="footnote-marker">1</sup><i class="footnote">Heb. yom.</i>, and the darkness Night<sup class="footnote-marker">2</sup><i class="footnote">Heb. laylah.</i>.`,
+			text: "God called the light Day, and the darkness Night.",
+			footnotes: []Footnote{
+				{Marker: "1", Text: "Heb. yom.", Offset: 24},
+				{Marker: "2", Text: "Heb. laylah.", Offset: 48},
+			},
+		},
+		{
+			name: "marker without a note",
+			html: `And there was evening<sup class="footnote-marker">3</sup> and there was morning, a first day.`,
+			text: "And there was evening and there was morning, a first day.",
+			footnotes: []Footnote{
+				{Marker: "3", Offset: 21},
+			},
+		},
+		{
+			name: "marker without a note before one with",
+			html: `evening<sup class="footnote-marker">3</sup> and morning<sup class="footnote-marker">4</sup><i class="footnote">Lit. “one day.”</i>`,
+			text: "evening and morning",
+			footnotes: []Footnote{
+				{Marker: "3", Offset: 7},
+				{Marker: "4", Text: "Lit. “one day.”", Offset: 19},
+			},
+		},
+		{
+			name: "note without a marker",
+			html: `בְּרֵאשִׁית<i class="footnote">פירוש</i> בָּרָא`,
+			text: "בְּרֵאשִׁית בָּרָא",
+			footnotes: []Footnote{
+				{Text: "פירוש", Offset: len("בְּרֵאשִׁית")},
+			},
+		},
+		{
+			name: "nested marker",
+			html: `the deep<sup class="footnote-marker"><sup>†</sup></sup><i class="footnote">Or “the abyss.”</i>`,
+			text: "the deep",
+			footnotes: []Footnote{
+				{Marker: "†", Text: "Or “the abyss.”", Offset: 8},
+			},
+		},
+		{
+			name: "other tags",
+			html: `<b>Rabbi Akiva</b> said:<br>“Beloved is <i>man</i>”<sup>1</sup>`,
+			text: "Rabbi Akiva said:\n“Beloved is man”1",
+		},
+		{
+			name: "script and style",
+			html: `text<script>alert("x")</script><style>b{}</style> kept`,
+			text: "text kept",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg := ParseSegment(tt.html)
+			assert.Equal(t, tt.text, seg.Text)
+			assert.Equal(t, tt.footnotes, seg.Footnotes)
+			for _, f := range seg.Footnotes {
+				assert.LessOrEqual(t, f.Offset, len(seg.Text))
+			}
+		})
+	}
+}
+
+func TestText_SegmentPairs(t *testing.T) {
+	text := &Text{
+		Text: []string{`In the beginning<sup class="footnote-marker">a</sup><i class="footnote">Or “When”</i>`, "the earth"},
+		He:   []string{"בְּרֵאשִׁית"},
+	}
+
+	var en, he []string
+	for e, h := range text.SegmentPairs() {
+		en = append(en, e.Text)
+		he = append(he, h.Text)
+	}
+	assert.Equal(t, []string{"In the beginning", "the earth"}, en)
+	assert.Equal(t, []string{"בְּרֵאשִׁית", ""}, he)
+	assert.Len(t, text.Segments()[0].Footnotes, 1)
+}
+
+func TestParseSegment_Entities(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		text     string
+		entities []Entity
+	}{
+		{
+			name: "ref link",
+			html: `As it is written <a class="refLink" href="/Genesis.1.1" data-ref="Genesis 1:1">Genesis 1:1</a>, so too here.`,
+			text: "As it is written Genesis 1:1, so too here.",
+			entities: []Entity{
+				{Kind: EntityRef, Start: 17, End: 28, Text: "Genesis 1:1", Ref: "Genesis 1:1"},
+			},
+		},
+		{
+			name: "ref link without data-ref",
+			html: `see <a class="refLink" href="/Rashi_on_Genesis.1.1.1">Rashi</a>`,
+			text: "see Rashi",
+			entities: []Entity{
+				{Kind: EntityRef, Start: 4, End: 9, Text: "Rashi", Ref: "Rashi on Genesis 1:1:1"},
+			},
+		},
+		{
+			name: "named entity",
+			html: `<a href="/topics/moses" class="namedEntityLink" data-slug="moses">Moses</a> went up the mountain`,
+			text: "Moses went up the mountain",
+			entities: []Entity{
+				{Kind: EntityNamedEntity, Start: 0, End: 5, Text: "Moses", Slug: "moses"},
+			},
+		},
+		{
+			name: "named entity without data-slug",
+			html: `and <a class="namedEntityLink" href="/topics/aaron">Aaron</a>`,
+			text: "and Aaron",
+			entities: []Entity{
+				{Kind: EntityNamedEntity, Start: 4, End: 9, Text: "Aaron", Slug: "aaron"},
+			},
+		},
+		{
+			name: "topic link",
+			html: `the laws of <a href="/topics/shabbat">Shabbat</a> and <a href="https://www.sefaria.org/topics/prayer?tab=sources">prayer</a>`,
+			text: "the laws of Shabbat and prayer",
+			entities: []Entity{
+				{Kind: EntityTopic, Start: 12, End: 19, Text: "Shabbat", Slug: "shabbat"},
+				{Kind: EntityTopic, Start: 24, End: 30, Text: "prayer", Slug: "prayer"},
+			},
+		},
+		{
+			name: "other links",
+			html: `<a href="https://example.com/">elsewhere</a>, <a href="/sheets/1234">a sheet</a> and <a>nothing</a>`,
+			text: "elsewhere, a sheet and nothing",
+		},
+		{
+			name: "hebrew and entities",
+			html: `כמו שפירש רש&quot;י &amp; <a class="refLink" href="/Genesis.1.1" data-ref="Genesis 1:1">בראשית א׳&nbsp;א׳</a> ועוד <a class="namedEntityLink" data-slug="moses">משה</a>`,
+			text: "כמו שפירש רש\"י & בראשית א׳\u00a0א׳ ועוד משה",
+			entities: []Entity{
+				{Kind: EntityRef, Start: len("כמו שפירש רש\"י & "), End: len("כמו שפירש רש\"י & בראשית א׳\u00a0א׳"), Text: "בראשית א׳\u00a0א׳", Ref: "Genesis 1:1"},
+				{Kind: EntityNamedEntity, Start: len("כמו שפירש רש\"י & בראשית א׳\u00a0א׳ ועוד "), End: len("כמו שפירש רש\"י & בראשית א׳\u00a0א׳ ועוד משה"), Text: "משה", Slug: "moses"},
+			},
+		},
+		{
+			name: "nested in other tags",
+			html: `<i>as in <a class="refLink" data-ref="Exodus 20:8">Exodus 20:8</a></i> and <b><a href="/topics/shabbat">Shabbat</a></b>`,
+			text: "as in Exodus 20:8 and Shabbat",
+			entities: []Entity{
+				{Kind: EntityRef, Start: 6, End: 17, Text: "Exodus 20:8", Ref: "Exodus 20:8"},
+				{Kind: EntityTopic, Start: 22, End: 29, Text: "Shabbat", Slug: "shabbat"},
+			},
+		},
+		{
+			name: "tags nested in link",
+			html: `<a class="refLink" data-ref="Berakhot 2a"><b>Berakhot</b> <i>2a</i></a>`,
+			text: "Berakhot 2a",
+			entities: []Entity{
+				{Kind: EntityRef, Start: 0, End: 11, Text: "Berakhot 2a", Ref: "Berakhot 2a"},
+			},
+		},
+		{
+			name: "link beside a footnote",
+			html: `<a class="refLink" data-ref="Genesis 1:1">Gen. 1:1</a><sup class="footnote-marker">a</sup><i class="footnote">See <a class="refLink" data-ref="Rashi on Genesis 1:1">Rashi</a>.</i> here`,
+			text: "Gen. 1:1 here",
+			entities: []Entity{
+				{Kind: EntityRef, Start: 0, End: 8, Text: "Gen. 1:1", Ref: "Genesis 1:1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg := ParseSegment(tt.html)
+			assert.Equal(t, tt.text, seg.Text)
+			assert.Equal(t, tt.entities, seg.Entities)
+			for _, e := range seg.Entities {
+				assert.Equal(t, e.Text, seg.Text[e.Start:e.End])
+			}
+		})
+	}
+}
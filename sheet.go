@@ -0,0 +1,90 @@
+package sefaria
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+	"github.com/ryanfaerman/go-sefaria/types"
+)
+
+type SheetService service
+
+type SheetTopic struct {
+	Slug    string      `json:"slug" normalize:"-"`
+	AsTyped string      `json:"asTyped"`
+	English string      `json:"en"`
+	Hebrew  bidi.String `json:"he"`
+}
+
+// SheetSummary is the abbreviated form of a sheet that appears in listings,
+// such as the sheets that belong to a collection.
+type SheetSummary struct {
+	ID            int          `json:"id" table:"ID"`
+	Title         string       `json:"title" table:"Title"`
+	Summary       string       `json:"summary"`
+	SheetURL      string       `json:"sheetUrl" normalize:"-"`
+	Status        string       `json:"status"`
+	OwnerName     string       `json:"ownerName" table:"Owner"`
+	OwnerImageURL string       `json:"ownerImageUrl" normalize:"-"`
+	Views         int          `json:"views" table:"Views"`
+	Created       types.Date   `json:"created"`
+	Modified      types.Date   `json:"modified"`
+	Topics        []SheetTopic `json:"topics"`
+}
+
+// SheetSource is a single block of a sheet. A source is either a citation of
+// a text (Ref is set), a comment by the sheet author, or free-standing
+// "outside" text. Media sources carry a URL instead of text.
+type SheetSource struct {
+	Node    int             `json:"node"`
+	Ref     string          `json:"ref,omitempty" normalize:"-"`
+	HeRef   string          `json:"heRef,omitempty" normalize:"-"`
+	Text    BilingualString `json:"text,omitzero"`
+	Comment string          `json:"comment,omitempty"`
+
+	OutsideText   string          `json:"outsideText,omitempty"`
+	OutsideBiText BilingualString `json:"outsideBiText,omitzero"`
+
+	Media string `json:"media,omitempty"`
+}
+
+type Sheet struct {
+	ID            int           `json:"id"`
+	Title         string        `json:"title"`
+	Summary       string        `json:"summary"`
+	Status        string        `json:"status"`
+	Owner         int           `json:"owner"`
+	OwnerName     string        `json:"ownerName"`
+	OwnerImageURL string        `json:"ownerImageUrl" normalize:"-"`
+	Views         int           `json:"views"`
+	DateCreated   types.Date    `json:"dateCreated"`
+	DateModified  types.Date    `json:"dateModified"`
+	Topics        []SheetTopic  `json:"topics"`
+	Sources       []SheetSource `json:"sources"`
+
+	// Collection is the slug of the collection the sheet is displayed under,
+	// if any.
+	Collection string `json:"displayedCollection"`
+}
+
+var ErrInvalidSheetID = errors.New("invalid sheet id")
+
+// Get the sheet with the given numeric id, including all of its sources.
+func (s *SheetService) Get(ctx context.Context, id int) (*Sheet, error) {
+	if id <= 0 {
+		return nil, ErrInvalidSheetID
+	}
+
+	u := s.client.BaseURL.JoinPath("/sheets", strconv.Itoa(id))
+	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(Sheet)
+	_, err = s.client.Do(req, out)
+	return out, err
+}
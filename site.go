@@ -0,0 +1,417 @@
+package sefaria
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Site builds and parses links to pages on a Sefaria website, as opposed to
+// its API.
+type Site struct {
+	URL *url.URL
+}
+
+// DefaultSite is www.sefaria.org.
+var DefaultSite = &Site{URL: &url.URL{Scheme: "https", Host: "www.sefaria.org"}}
+
+var (
+	ErrNotSefariaURL = errors.New("not a sefaria url")
+	ErrUnknownPage   = errors.New("unknown sefaria page")
+)
+
+// NewSite creates a Site for the website at base, e.g.
+// "https://www.sefaria.org.il".
+func NewSite(base string) (*Site, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotSefariaURL, base)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return &Site{URL: u}, nil
+}
+
+// Site returns the website served alongside the client's API, found by
+// dropping the trailing "/api" from its BaseURL.
+func (c *Client) Site() *Site {
+	u := *c.BaseURL
+	u.Path = strings.TrimSuffix(strings.TrimRight(u.Path, "/"), "/api")
+	u.RawPath, u.RawQuery, u.Fragment = "", "", ""
+	return &Site{URL: &u}
+}
+
+// LanguageMode selects the languages a page is shown in.
+type LanguageMode string
+
+const (
+	LanguageEnglish   LanguageMode = "en"
+	LanguageHebrew    LanguageMode = "he"
+	LanguageBilingual LanguageMode = "bi"
+)
+
+// Panel is a tool or set of connections opened in the sidebar of the reader.
+// Besides the constants below, a panel may also be a category of
+// connections, such as "Commentary", or the collective title of a
+// commentary, such as "Rashi".
+type Panel string
+
+const (
+	PanelAll          Panel = "all"
+	PanelAbout        Panel = "About"
+	PanelTranslations Panel = "Translations"
+	PanelVersions     Panel = "Versions"
+	PanelSheets       Panel = "Sheets"
+	PanelNotes        Panel = "Notes"
+	PanelTopics       Panel = "Topics"
+	PanelLexicon      Panel = "Lexicon"
+	PanelManuscripts  Panel = "Manuscripts"
+)
+
+type ReaderURLOptions struct {
+	// The languages to show the text in.
+	Language LanguageMode
+
+	// The versions to show. Hebrew versions are shown on the Hebrew side of
+	// the reader and all others on the English side.
+	Versions []TextVersion
+
+	// The sidebar panel to open next to the text.
+	With Panel
+
+	// Additional texts to open side by side with the first.
+	Panels []ReaderPanel
+}
+
+// ReaderPanel is an additional text open in the reader.
+type ReaderPanel struct {
+	Ref      string
+	Language LanguageMode
+	Versions []TextVersion
+	With     Panel
+}
+
+type SheetURLOptions struct {
+	Language LanguageMode
+}
+
+type TopicURLOptions struct {
+	// The tab to open, e.g. "sources" or "sheets".
+	Tab string
+}
+
+type SearchURLOptions struct {
+	// The results to show, either "text" or "sheet". Defaults to "text".
+	Tab string
+
+	// The order of the results, either "relevance" or "chronological".
+	Sort string
+
+	// Only match the query exactly, excluding variants of its words.
+	Exact bool
+}
+
+// Reader returns the link to read a ref, e.g.
+// https://www.sefaria.org/Genesis.1.1-3?lang=bi&with=Rashi
+func (s *Site) Reader(ref string, opts *ReaderURLOptions) *url.URL {
+	u := s.page(refPath(ref))
+	if opts == nil {
+		return u
+	}
+
+	v := url.Values{}
+	addPanel(v, "", opts.Language, opts.Versions, opts.With)
+	for i, p := range opts.Panels {
+		n := strconv.Itoa(i + 2)
+		v.Set("p"+n, refPath(p.Ref))
+		addPanel(v, n, p.Language, p.Versions, p.With)
+	}
+	u.RawQuery = encodeQuery(v)
+	return u
+}
+
+func addPanel(v url.Values, n string, lang LanguageMode, versions []TextVersion, with Panel) {
+	if lang != "" {
+		v.Set("lang"+n, string(lang))
+	}
+	for _, version := range versions {
+		key := "ven"
+		if isHebrewLanguage(version.Language) {
+			key = "vhe"
+		}
+		v.Set(key+n, strings.ReplaceAll(version.String(), " ", "_"))
+	}
+	if with != "" {
+		key := "with"
+		if n != "" {
+			key = "w" + n
+		}
+		v.Set(key, string(with))
+	}
+}
+
+// Sheet returns the link to a source sheet, e.g.
+// https://www.sefaria.org/sheets/1234
+func (s *Site) Sheet(id int, opts *SheetURLOptions) *url.URL {
+	u := s.page("sheets", strconv.Itoa(id))
+	if opts != nil && opts.Language != "" {
+		u.RawQuery = url.Values{"lang": {string(opts.Language)}}.Encode()
+	}
+	return u
+}
+
+// Topic returns the link to a topic page, e.g.
+// https://www.sefaria.org/topics/shabbat
+func (s *Site) Topic(slug string, opts *TopicURLOptions) *url.URL {
+	u := s.page("topics", slug)
+	if opts != nil && opts.Tab != "" {
+		u.RawQuery = url.Values{"tab": {opts.Tab}}.Encode()
+	}
+	return u
+}
+
+// Search returns the link to the results of a search, e.g.
+// https://www.sefaria.org/search?q=shabbat&tab=text
+func (s *Site) Search(query string, opts *SearchURLOptions) *url.URL {
+	u := s.page("search")
+	if opts == nil {
+		opts = &SearchURLOptions{}
+	}
+
+	tab := opts.Tab
+	if tab == "" {
+		tab = "text"
+	}
+	v := url.Values{"q": {query}, "tab": {tab}}
+	prefix := tab[:1]
+	if opts.Sort != "" {
+		v.Set(prefix+"sort", opts.Sort)
+	}
+	if opts.Exact {
+		v.Set(prefix+"var", "0")
+	}
+	u.RawQuery = v.Encode()
+	return u
+}
+
+func (s *Site) page(elem ...string) *url.URL {
+	u := *s.URL
+	for i := range elem {
+		elem[i] = url.PathEscape(elem[i])
+	}
+	// Commas are left as is, as they are in the titles of many texts.
+	path := strings.ReplaceAll(strings.Join(elem, "/"), "%2C", ",")
+	u.RawPath = strings.TrimRight(u.EscapedPath(), "/") + "/" + path
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return &u
+}
+
+// PageKind is the kind of page a link leads to.
+type PageKind string
+
+const (
+	PageReader PageKind = "reader"
+	PageSheet  PageKind = "sheet"
+	PageTopic  PageKind = "topic"
+	PageSearch PageKind = "search"
+)
+
+// Page is a link to a Sefaria website, parsed into the state it describes.
+// Only the fields for its kind of page are set.
+type Page struct {
+	Kind PageKind
+
+	Ref           string
+	ReaderOptions ReaderURLOptions
+
+	SheetID      int
+	SheetOptions SheetURLOptions
+
+	Topic        string
+	TopicOptions TopicURLOptions
+
+	Query         string
+	SearchOptions SearchURLOptions
+
+	site *Site
+}
+
+// URL rebuilds the link to the page.
+func (p *Page) URL() *url.URL {
+	site := p.site
+	if site == nil {
+		site = DefaultSite
+	}
+	switch p.Kind {
+	case PageSheet:
+		return site.Sheet(p.SheetID, &p.SheetOptions)
+	case PageTopic:
+		return site.Topic(p.Topic, &p.TopicOptions)
+	case PageSearch:
+		return site.Search(p.Query, &p.SearchOptions)
+	}
+	return site.Reader(p.Ref, &p.ReaderOptions)
+}
+
+func (p *Page) String() string {
+	return p.URL().String()
+}
+
+// Parse reads a link to the site. Relative links, such as the
+// "Genesis.1.1-6.8" found in calendars, are taken to be on the site, and
+// absolute links must be to the site's host or another sefaria.org host,
+// which the page's URL keeps.
+func (s *Site) Parse(raw string) (*Page, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != s.URL.Host && !isSefariaHost(u.Host) {
+		return nil, fmt.Errorf("%w: %s", ErrNotSefariaURL, raw)
+	}
+
+	path := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || u.Host == s.URL.Host {
+		path = strings.TrimPrefix(path, strings.TrimPrefix(s.URL.Path, "/"))
+		path = strings.TrimPrefix(path, "/")
+	}
+	section, rest, _ := strings.Cut(strings.TrimRight(path, "/"), "/")
+	q := u.Query()
+	p := &Page{site: s}
+	if u.Host != "" && u.Host != s.URL.Host {
+		// Links to another of Sefaria's hosts are rebuilt on that host.
+		scheme := cmp.Or(u.Scheme, s.URL.Scheme)
+		p.site = &Site{URL: &url.URL{Scheme: scheme, Host: u.Host}}
+	}
+
+	switch section {
+	case "":
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPage, raw)
+	case "sheets":
+		id, _, _ := strings.Cut(rest, ".")
+		p.Kind = PageSheet
+		p.SheetID, err = strconv.Atoi(id)
+		if err != nil || p.SheetID <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSheetID, raw)
+		}
+		p.SheetOptions.Language = LanguageMode(q.Get("lang"))
+	case "topics":
+		if rest == "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPage, raw)
+		}
+		p.Kind = PageTopic
+		p.Topic = rest
+		p.TopicOptions.Tab = q.Get("tab")
+	case "search":
+		p.Kind = PageSearch
+		p.Query = q.Get("q")
+		p.SearchOptions.Tab = q.Get("tab")
+		prefix := "t"
+		if p.SearchOptions.Tab == "sheet" {
+			prefix = "s"
+		}
+		p.SearchOptions.Sort = q.Get(prefix + "sort")
+		p.SearchOptions.Exact = q.Get(prefix+"var") == "0"
+	default:
+		if rest != "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPage, raw)
+		}
+		p.Kind = PageReader
+		p.Ref = parseRefPath(section)
+		p.ReaderOptions = ReaderURLOptions{
+			Language: LanguageMode(q.Get("lang")),
+			Versions: parseVersions(q, ""),
+			With:     Panel(q.Get("with")),
+		}
+		for n := 2; q.Has("p" + strconv.Itoa(n)); n++ {
+			i := strconv.Itoa(n)
+			p.ReaderOptions.Panels = append(p.ReaderOptions.Panels, ReaderPanel{
+				Ref:      parseRefPath(q.Get("p" + i)),
+				Language: LanguageMode(q.Get("lang" + i)),
+				Versions: parseVersions(q, i),
+				With:     Panel(q.Get("w" + i)),
+			})
+		}
+	}
+	return p, nil
+}
+
+// ParseURL parses a link to sefaria.org. See Site.Parse.
+func ParseURL(raw string) (*Page, error) {
+	return DefaultSite.Parse(raw)
+}
+
+func isSefariaHost(host string) bool {
+	host = strings.TrimPrefix(host, "www.")
+	return host == "sefaria.org" || host == "sefaria.org.il" || strings.HasSuffix(host, ".sefaria.org")
+}
+
+// refPath formats a ref as it appears in the path of a link, with
+// underscores for spaces and periods between the sections, e.g.
+// "Rashi_on_Genesis.1.1-3".
+func refPath(ref string) string {
+	parsed, err := ParseRef(ref)
+	if err != nil {
+		return ""
+	}
+	path := strings.ReplaceAll(parsed.Book, " ", "_")
+	if len(parsed.Sections) > 0 {
+		address := strings.TrimPrefix(parsed.String(), parsed.Book+" ")
+		path += "." + strings.ReplaceAll(address, ":", ".")
+	}
+	return path
+}
+
+// parseRefPath is the inverse of refPath. The address is the longest run of
+// sections at the end of the path.
+func parseRefPath(path string) string {
+	path = strings.ReplaceAll(path, "_", " ")
+	for i := 0; i < len(path); i++ {
+		if path[i] != '.' {
+			continue
+		}
+		address := strings.ReplaceAll(path[i+1:], ".", ":")
+		if _, _, ok := parseAddress(address); ok {
+			return path[:i] + " " + address
+		}
+	}
+	return path
+}
+
+// parseVersions reads the versions of a panel. Versions are written either as
+// "language|Title" or, in older links, as the bare title.
+func parseVersions(q url.Values, n string) []TextVersion {
+	var versions []TextVersion
+	for _, key := range []string{"vhe", "ven"} {
+		raw := q.Get(key + n)
+		if raw == "" {
+			continue
+		}
+		language, title, ok := strings.Cut(strings.ReplaceAll(raw, "_", " "), "|")
+		if !ok {
+			title = language
+			language = "english"
+			if key == "vhe" {
+				language = "hebrew"
+			}
+		}
+		versions = append(versions, TextVersion{Language: language, Title: title})
+	}
+	return versions
+}
+
+func isHebrewLanguage(language string) bool {
+	return language == "he" || language == "hebrew"
+}
+
+// encodeQuery encodes v like url.Values.Encode but leaves the separators
+// used within versions unescaped, which keeps links readable.
+func encodeQuery(v url.Values) string {
+	return strings.NewReplacer("%7C", "|", "%2C", ",").Replace(v.Encode())
+}
@@ -0,0 +1,258 @@
+package sefaria
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSite_Reader(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+		opts *ReaderURLOptions
+		want string
+	}{
+		{"ref", "Genesis 1:1", nil, "https://www.sefaria.org/Genesis.1.1"},
+		{"range", "Genesis 1:1-3", nil, "https://www.sefaria.org/Genesis.1.1-3"},
+		{"range across chapters", "Genesis 1:1-6:8", nil, "https://www.sefaria.org/Genesis.1.1-6.8"},
+		{"book", "Genesis", nil, "https://www.sefaria.org/Genesis"},
+		{"spaces in title", "Rashi on Genesis 1:1:1", nil, "https://www.sefaria.org/Rashi_on_Genesis.1.1.1"},
+		{"comma in title", "Shulchan Arukh, Orach Chayim 1:1", nil, "https://www.sefaria.org/Shulchan_Arukh,_Orach_Chayim.1.1"},
+		{"daf", "Berakhot 2a:3-5", nil, "https://www.sefaria.org/Berakhot.2a.3-5"},
+		{
+			"language and panel",
+			"Genesis 1:1-3",
+			&ReaderURLOptions{Language: LanguageBilingual, With: "Rashi"},
+			"https://www.sefaria.org/Genesis.1.1-3?lang=bi&with=Rashi",
+		},
+		{
+			"versions",
+			"Genesis 1",
+			&ReaderURLOptions{Versions: []TextVersion{
+				{Language: "english", Title: "The Contemporary Torah, Jewish Publication Society, 2006"},
+				{Language: "hebrew", Title: "Miqra according to the Masorah"},
+			}},
+			"https://www.sefaria.org/Genesis.1?ven=english|The_Contemporary_Torah,_Jewish_Publication_Society,_2006&vhe=hebrew|Miqra_according_to_the_Masorah",
+		},
+		{
+			"panels",
+			"Rashi on Genesis 1:1:1",
+			&ReaderURLOptions{Panels: []ReaderPanel{
+				{Ref: "Genesis 1:1", Language: LanguageHebrew, With: PanelTranslations},
+				{Ref: "Exodus 2", Versions: []TextVersion{{Language: "hebrew", Title: "Tanach with Nikkud"}}},
+			}},
+			"https://www.sefaria.org/Rashi_on_Genesis.1.1.1?lang2=he&p2=Genesis.1.1&p3=Exodus.2&vhe3=hebrew|Tanach_with_Nikkud&w2=Translations",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DefaultSite.Reader(tt.ref, tt.opts).String())
+		})
+	}
+}
+
+func TestSite_Links(t *testing.T) {
+	assert.Equal(t, "https://www.sefaria.org/sheets/1234?lang=he",
+		DefaultSite.Sheet(1234, &SheetURLOptions{Language: LanguageHebrew}).String())
+	assert.Equal(t, "https://www.sefaria.org/topics/shabbat?tab=sheets",
+		DefaultSite.Topic("shabbat", &TopicURLOptions{Tab: "sheets"}).String())
+	assert.Equal(t, "https://www.sefaria.org/search?q=shabbat&tab=text",
+		DefaultSite.Search("shabbat", nil).String())
+	assert.Equal(t, "https://www.sefaria.org/search?q=shabbat+candles&ssort=chronological&svar=0&tab=sheet",
+		DefaultSite.Search("shabbat candles", &SearchURLOptions{Tab: "sheet", Sort: "chronological", Exact: true}).String())
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Page
+	}{
+		{
+			name: "reader",
+			raw:  "https://www.sefaria.org/Genesis.1.1-3?lang=bi&with=Rashi",
+			want: Page{Kind: PageReader, Ref: "Genesis 1:1-3", ReaderOptions: ReaderURLOptions{
+				Language: LanguageBilingual,
+				With:     "Rashi",
+			}},
+		},
+		{
+			name: "versions",
+			raw:  "https://www.sefaria.org/Genesis.1?ven=english|The_Contemporary_Torah,_Jewish_Publication_Society,_2006&vhe=hebrew|Miqra_according_to_the_Masorah",
+			want: Page{Kind: PageReader, Ref: "Genesis 1", ReaderOptions: ReaderURLOptions{
+				Versions: []TextVersion{
+					{Language: "hebrew", Title: "Miqra according to the Masorah"},
+					{Language: "english", Title: "The Contemporary Torah, Jewish Publication Society, 2006"},
+				},
+			}},
+		},
+		{
+			name: "bare version titles",
+			raw:  "https://www.sefaria.org/Genesis.1?ven=The_Koren_Jerusalem_Bible&vhe=Tanach_with_Nikkud",
+			want: Page{Kind: PageReader, Ref: "Genesis 1", ReaderOptions: ReaderURLOptions{
+				Versions: []TextVersion{
+					{Language: "hebrew", Title: "Tanach with Nikkud"},
+					{Language: "english", Title: "The Koren Jerusalem Bible"},
+				},
+			}},
+		},
+		{
+			name: "panels",
+			raw:  "https://www.sefaria.org/Rashi_on_Genesis.1.1.1?p2=Genesis.1.1&lang2=he&w2=Translations&p3=Exodus.2&vhe3=hebrew|Tanach_with_Nikkud",
+			want: Page{Kind: PageReader, Ref: "Rashi on Genesis 1:1:1", ReaderOptions: ReaderURLOptions{
+				Panels: []ReaderPanel{
+					{Ref: "Genesis 1:1", Language: LanguageHebrew, With: PanelTranslations},
+					{Ref: "Exodus 2", Versions: []TextVersion{{Language: "hebrew", Title: "Tanach with Nikkud"}}},
+				},
+			}},
+		},
+		{
+			name: "spaces in path",
+			raw:  "https://www.sefaria.org/Shulchan%20Arukh,%20Orach_Chayim.1.1",
+			want: Page{Kind: PageReader, Ref: "Shulchan Arukh, Orach Chayim 1:1"},
+		},
+		{
+			name: "relative",
+			raw:  "/Berakhot.2a",
+			want: Page{Kind: PageReader, Ref: "Berakhot 2a"},
+		},
+		{
+			name: "relative without slash",
+			raw:  "Genesis.1.1-6.8",
+			want: Page{Kind: PageReader, Ref: "Genesis 1:1-6:8"},
+		},
+		{
+			name: "sheet",
+			raw:  "https://www.sefaria.org/sheets/1234.5?lang=he",
+			want: Page{Kind: PageSheet, SheetID: 1234, SheetOptions: SheetURLOptions{Language: LanguageHebrew}},
+		},
+		{
+			name: "topic",
+			raw:  "https://www.sefaria.org/topics/shabbat?tab=sheets",
+			want: Page{Kind: PageTopic, Topic: "shabbat", TopicOptions: TopicURLOptions{Tab: "sheets"}},
+		},
+		{
+			name: "search",
+			raw:  "https://www.sefaria.org/search?q=shabbat+candles&tab=sheet&ssort=chronological&svar=0",
+			want: Page{Kind: PageSearch, Query: "shabbat candles", SearchOptions: SearchURLOptions{
+				Tab:   "sheet",
+				Sort:  "chronological",
+				Exact: true,
+			}},
+		},
+		{
+			name: "hebrew search",
+			raw:  "https://www.sefaria.org/search?q=%D7%A9%D7%91%D7%AA&tab=text&tsort=relevance",
+			want: Page{Kind: PageSearch, Query: "שבת", SearchOptions: SearchURLOptions{Tab: "text", Sort: "relevance"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseURL(tt.raw)
+			require.NoError(t, err)
+			got.site = nil
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestParseURL_Errors(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{"https://example.com/Genesis.1", ErrNotSefariaURL},
+		{"https://www.sefaria.org/", ErrUnknownPage},
+		{"https://www.sefaria.org/topics/", ErrUnknownPage},
+		{"https://www.sefaria.org/Genesis.1/extra", ErrUnknownPage},
+		{"https://www.sefaria.org/sheets/abc", ErrInvalidSheetID},
+		{"https://www.sefaria.org/sheets/0", ErrInvalidSheetID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			_, err := ParseURL(tt.raw)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+// TestPage_RoundTrip checks that parsing a link and building it again gives
+// the same link, up to the order of its query.
+func TestPage_RoundTrip(t *testing.T) {
+	links := []string{
+		"https://www.sefaria.org/Genesis.1.1",
+		"https://www.sefaria.org/Genesis.1.1-3?lang=bi&with=Rashi",
+		"https://www.sefaria.org/Genesis.1.1-6.8?lang=he",
+		"https://www.sefaria.org/Rashi_on_Genesis.1.1.1?with=all",
+		"https://www.sefaria.org/Shulchan_Arukh,_Orach_Chayim.1.1",
+		"https://www.sefaria.org/Berakhot.2a.3-5",
+		"https://www.sefaria.org/Genesis.1?ven=english|The_Contemporary_Torah,_Jewish_Publication_Society,_2006&vhe=hebrew|Miqra_according_to_the_Masorah",
+		"https://www.sefaria.org/Rashi_on_Genesis.1.1.1?p2=Genesis.1.1&lang2=he&w2=Translations&p3=Exodus.2&vhe3=hebrew|Tanach_with_Nikkud",
+		"https://www.sefaria.org/sheets/1234?lang=he",
+		"https://www.sefaria.org/topics/shabbat?tab=sheets",
+		"https://www.sefaria.org/search?q=shabbat+candles&tab=sheet&ssort=chronological&svar=0",
+		"https://www.sefaria.org.il/Genesis.1?lang=he",
+	}
+	for _, raw := range links {
+		t.Run(raw, func(t *testing.T) {
+			page, err := ParseURL(raw)
+			require.NoError(t, err)
+			assertSameURL(t, raw, page.String())
+
+			again, err := ParseURL(page.String())
+			require.NoError(t, err)
+			assert.Equal(t, page, again)
+		})
+	}
+}
+
+func TestSite_Parse_BasePath(t *testing.T) {
+	site, err := NewSite("https://sefaria.example.com/mirror/")
+	require.NoError(t, err)
+
+	page, err := site.Parse("https://sefaria.example.com/mirror/Genesis.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "Genesis 1:1", page.Ref)
+	assert.Equal(t, "https://sefaria.example.com/mirror/Genesis.1.1", page.String())
+
+	page, err = site.Parse("/Genesis.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sefaria.example.com/mirror/Genesis.1.1", page.String())
+}
+
+func TestScheduledLearning_Page(t *testing.T) {
+	tests := []struct {
+		url  string
+		ref  string
+		want string
+	}{
+		{"Genesis.1.1-6.8", "Genesis 1:1-6:8", "https://www.sefaria.org/Genesis.1.1-6.8"},
+		{"/Berakhot.2a", "Berakhot 2a", "https://www.sefaria.org/Berakhot.2a"},
+		{"Pirkei_Avot.1", "Pirkei Avot 1", "https://www.sefaria.org/Pirkei_Avot.1"},
+		{"Mishneh_Torah,_Prayer_and_the_Priestly_Blessing.1-3", "Mishneh Torah, Prayer and the Priestly Blessing 1-3", "https://www.sefaria.org/Mishneh_Torah,_Prayer_and_the_Priestly_Blessing.1-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			page, err := ScheduledLearning{URL: tt.url}.Page()
+			require.NoError(t, err)
+			assert.Equal(t, PageReader, page.Kind)
+			assert.Equal(t, tt.ref, page.Ref)
+			assert.Equal(t, tt.want, page.String())
+		})
+	}
+}
+
+// assertSameURL asserts that two links are the same but for the order of
+// their query parameters.
+func assertSameURL(t *testing.T, want, got string) {
+	t.Helper()
+	w, err := url.Parse(want)
+	require.NoError(t, err)
+	g, err := url.Parse(got)
+	require.NoError(t, err)
+	assert.Equal(t, w.Scheme+"://"+w.Host+w.Path, g.Scheme+"://"+g.Host+g.Path, "link to %s", got)
+	assert.Equal(t, w.Query(), g.Query(), "query of %s", got)
+}
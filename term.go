@@ -47,7 +47,7 @@ type Term struct {
 	// of a title string followed optionally by a section string or a segment
 	// string. A title string is any one of the known text titles or title
 	// variants in the Sefaria Database.
-	Ref string `json:"ref"`
+	Ref string `json:"ref" normalize:"-"`
 
 	// The category of a specific term.
 	Category string `json:"category"`
@@ -97,7 +97,7 @@ type TermCompletion struct {
 	Title      string   `json:"title" table:"Title"`
 	Key        string   `json:"key" table:"Key"`
 	Type       string   `json:"type" table:"Type"`
-	PictureURL string   `json:"pic,omitempty"`
+	PictureURL string   `json:"pic,omitempty" normalize:"-"`
 	Primary    bool     `json:"is_primary" table:"Primary"`
 	Order      int      `json:"order"`
 	TopicPools []string `json:"topic_pools,omitempty"`
@@ -135,10 +135,10 @@ type TermCompletions struct {
 	IsRange bool `json:"is_range"`
 
 	// If type=ref, this returns the canonical ref for the submitted text.
-	Ref string `json:"ref"`
+	Ref string `json:"ref" normalize:"-"`
 
 	// If type=ref, this returns the URL path to link to the submitted text on Sefaria.org
-	URL string `json:"url"`
+	URL string `json:"url" normalize:"-"`
 
 	// If type=ref, this returns the canonical name of the index of the submitted text.
 	Index string `json:"index"`
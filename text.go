@@ -6,14 +6,15 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/ryanfaerman/go-sefaria/bidi"
 	"github.com/ryanfaerman/go-sefaria/types"
 )
 
 type TextService service
 
 type Text struct {
-	Ref                         string    `json:"ref"`
-	HeRef                       string    `json:"heRef"`
+	Ref                         string    `json:"ref" normalize:"-"`
+	HeRef                       string    `json:"heRef" normalize:"-"`
 	IsComplex                   bool      `json:"isComplex"`
 	Text                        []string  `json:"text"`
 	He                          []string  `json:"he"`
@@ -41,29 +42,29 @@ type Text struct {
 	IsDependant                 bool      `json:"isDependant"`
 	IndexTitle                  string    `json:"indexTitle"`
 	HeIndexTitle                string    `json:"heIndexTitle"`
-	SectionRef                  string    `json:"sectionRef"`
-	FirstAvailableSectionRef    string    `json:"firstAvailableSectionRef"`
-	HeSectionRef                string    `json:"heSectionRef"`
+	SectionRef                  string    `json:"sectionRef" normalize:"-"`
+	FirstAvailableSectionRef    string    `json:"firstAvailableSectionRef" normalize:"-"`
+	HeSectionRef                string    `json:"heSectionRef" normalize:"-"`
 	IsSpanning                  bool      `json:"isSpanning"`
 	HeVersionTitle              string    `json:"heVersionTitle"`
 	HeVersionTitleInHebrew      string    `json:"heVersionTitleInHebrew"`
 	HeShortVersionTitle         string    `json:"heShortVersionTitle"`
 	HeShortVersionTitleInHebrew string    `json:"heShortVersionTitleInHebrew"`
-	HeVersionSource             string    `json:"heVersionSource"`
+	HeVersionSource             string    `json:"heVersionSource" normalize:"-"`
 	HeVersionStatus             string    `json:"heVersionStatus"`
 	HeVersionNotes              string    `json:"heVersionNotes"`
 	HeExtendedNotes             string    `json:"heExtendedNotes"`
 	HeExtendedNotesHebrew       string    `json:"heExtendedNotesHebrew"`
 	HeVersionNotesInHebrew      string    `json:"heVersionNotesInHebrew"`
 	HeDigitizedBySefaria        bool      `json:"heDigitizedBySefaria"`
-	HeLicense                   string    `json:"heLicense"`
+	HeLicense                   string    `json:"heLicense" normalize:"-"`
 	FormatHeAsPoetry            bool      `json:"formatHeAsPoetry"`
 	Title                       string    `json:"title"`
 	HeBook                      string    `json:"heBook"`
 	Alts                        []any     `json:"alts"`
 	IndexOffsetsByDepth         struct{}  `json:"index_offsets_by_depth"`
-	Next                        string    `json:"next"`
-	Prev                        string    `json:"prev"`
+	Next                        string    `json:"next" normalize:"-"`
+	Prev                        string    `json:"prev" normalize:"-"`
 	Commentary                  []any     `json:"commentary"`
 	Sheets                      []any     `json:"sheets"`
 	Layer                       []any     `json:"layer"`
@@ -72,10 +73,10 @@ type Text struct {
 type Version struct {
 	Title                     string                  `json:"title"`
 	VersionTitle              string                  `json:"versionTitle"`
-	VersionSource             string                  `json:"versionSource"`
+	VersionSource             string                  `json:"versionSource" normalize:"-"`
 	Language                  string                  `json:"language"`
 	Status                    string                  `json:"status"`
-	License                   string                  `json:"license"`
+	License                   string                  `json:"license" normalize:"-"`
 	VersionNotes              string                  `json:"versionNotes"`
 	DigitizedBySefaria        types.StringOr[bool]    `json:"digitizedBySefaria"`
 	Priority                  types.StringOr[float32] `json:"priority"`
@@ -83,16 +84,16 @@ type Version struct {
 	VersionNotesInHebrew      string                  `json:"versionNotesInHebrew"`
 	ExtendedNotes             string                  `json:"extendedNotes"`
 	ExtendedNotesHebrew       string                  `json:"extendedNotesHebrew"`
-	PurchaseInformationImage  string                  `json:"purchaseInformationImage"`
-	PurchaseInformationURL    string                  `json:"purchaseInformationURL"`
+	PurchaseInformationImage  string                  `json:"purchaseInformationImage" normalize:"-"`
+	PurchaseInformationURL    string                  `json:"purchaseInformationURL" normalize:"-"`
 	ShortVersionTitle         string                  `json:"shortVersionTitle"`
 	ShortVersionTitleInHebrew string                  `json:"shortVersionTitleInHebrew"`
-	FirstSectionRef           string                  `json:"firstSectionRef"`
+	FirstSectionRef           string                  `json:"firstSectionRef" normalize:"-"`
 
 	FormatAsPoetry         string `json:"formatAsPoetry"`
 	Method                 string `json:"method"`
-	HeversionSource        string `json:"heversionSource"`
-	VersionURL             string `json:"versionUrl"`
+	HeversionSource        string `json:"heversionSource" normalize:"-"`
+	VersionURL             string `json:"versionUrl" normalize:"-"`
 	HasManuallyWrappedRefs string `json:"hasManuallyWrappedRefs"`
 	ActualLanguage         string `json:"actualLanguage"`
 	LanguageFamilyName     string `json:"languageFamilyName"`
@@ -101,6 +102,36 @@ type Version struct {
 	Direction              string `json:"direction"`
 }
 
+// rtlLanguages are the languages of Sefaria's versions that are written right
+// to left, by their ISO 639 codes.
+var rtlLanguages = map[string]bool{
+	"ar":  true, // Arabic
+	"arc": true, // Aramaic
+	"fa":  true, // Persian
+	"he":  true, // Hebrew
+	"jrb": true, // Judeo-Arabic
+	"syc": true, // Classical Syriac
+	"yi":  true, // Yiddish
+}
+
+// Dir returns the direction the version is written in. It is taken from
+// Direction when Sefaria gives one, and otherwise from the version's language.
+func (v Version) Dir() bidi.Dir {
+	if d := bidi.ParseDir(v.Direction); d != bidi.Auto {
+		return d
+	}
+	for _, lang := range []string{v.ActualLanguage, v.Language} {
+		if lang == "" {
+			continue
+		}
+		if rtlLanguages[lang] {
+			return bidi.RightToLeft
+		}
+		return bidi.LeftToRight
+	}
+	return bidi.Auto
+}
+
 type TextFormat string
 
 const (
@@ -6,14 +6,14 @@ import (
 )
 
 type Manuscript struct {
-	ManuscriptSlug    string   `json:"manuscript_slug"`
+	ManuscriptSlug    string   `json:"manuscript_slug" normalize:"-"`
 	PageID            string   `json:"page_id"`
-	ImageURL          string   `json:"image_url"`
-	ThumbnailURL      string   `json:"thumbnail_url"`
-	AnchorRef         string   `json:"anchorRef"`
-	AnchorRefExpanded []string `json:"anchorRefExpanded"`
+	ImageURL          string   `json:"image_url" normalize:"-"`
+	ThumbnailURL      string   `json:"thumbnail_url" normalize:"-"`
+	AnchorRef         string   `json:"anchorRef" normalize:"-"`
+	AnchorRefExpanded []string `json:"anchorRefExpanded" normalize:"-"`
 	Manuscript        struct {
-		Slug          string `json:"slug"`
+		Slug          string `json:"slug" normalize:"-"`
 		Title         string `json:"title"`
 		HeTitle       string `json:"he_title"`
 		Source        string `json:"source"`
@@ -9,7 +9,7 @@ type Translation struct {
 	Category     string `json:"category"`
 	Name         string `json:"name"`
 	Title        string `json:"title"`
-	URL          string `json:"url"`
+	URL          string `json:"url" normalize:"-"`
 	VersionTitle string `json:"versionTitle"`
 	RTLLanguage  string `json:"rtlLanguage"`
 }
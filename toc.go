@@ -0,0 +1,76 @@
+package sefaria
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/ryanfaerman/go-sefaria/bidi"
+)
+
+// TOCNode is a single node of Sefaria's table of contents. A node is either a
+// category, in which case Category is set and Contents holds its children, or
+// a text, in which case Title is set.
+type TOCNode struct {
+	Category   string      `json:"category,omitempty"`
+	HeCategory bidi.String `json:"heCategory,omitempty"`
+
+	Title   string      `json:"title,omitempty" table:"Title"`
+	HeTitle bidi.String `json:"heTitle,omitempty" table:"Hebrew"`
+
+	Categories      []string `json:"categories,omitempty"`
+	PrimaryCategory string   `json:"primary_category,omitempty"`
+	Corpus          string   `json:"corpus,omitempty"`
+	Order           float64  `json:"order,omitempty"`
+
+	EnShortDesc string      `json:"enShortDesc,omitempty"`
+	HeShortDesc bidi.String `json:"heShortDesc,omitempty"`
+
+	// Commentaries and other dependent texts name the texts they depend on
+	// and the collective title of their author, e.g. "Rashi".
+	Dependence        string      `json:"dependence,omitempty"`
+	BaseTextTitles    []string    `json:"base_text_titles,omitempty"`
+	CollectiveTitle   string      `json:"collectiveTitle,omitempty"`
+	HeCollectiveTitle bidi.String `json:"heCollectiveTitle,omitempty"`
+
+	Contents []TOCNode `json:"contents,omitempty"`
+}
+
+// IsCategory reports whether the node groups other nodes rather than
+// representing a text.
+func (n TOCNode) IsCategory() bool {
+	return n.Category != ""
+}
+
+// Texts iterates over every text beneath the node, depth first.
+func (n TOCNode) Texts() iter.Seq[TOCNode] {
+	return func(yield func(TOCNode) bool) {
+		n.walk(yield)
+	}
+}
+
+func (n TOCNode) walk(yield func(TOCNode) bool) bool {
+	if !n.IsCategory() && n.Title != "" {
+		return yield(n)
+	}
+	for _, child := range n.Contents {
+		if !child.walk(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// TableOfContents returns the same table of contents as Contents, decoded
+// into typed nodes.
+func (s *IndexService) TableOfContents(ctx context.Context) ([]TOCNode, error) {
+	u := s.client.BaseURL.JoinPath("index")
+	req, err := s.client.NewRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	toc := make([]TOCNode, 0)
+	_, err = s.client.Do(req, &toc)
+	return toc, err
+}
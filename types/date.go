@@ -56,3 +56,8 @@ func (d *Date) UnmarshalJSON(data []byte) error {
 func (d Date) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.Format(dateLayout))
 }
+
+// String returns the date formatted as "2006-01-02", as it is marshaled.
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}